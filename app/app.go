@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -35,6 +36,8 @@ func New(d2Client *destiny2.Client, bot *discordgo.Session, repo *Repo) *App {
 		wg:       &sync.WaitGroup{},
 	}
 
+	a.bot.AddHandler(a.HandleMessage)
+
 	// Starting go routines when bot is ready
 	a.bot.AddHandlerOnce(func(_ *discordgo.Session, e *discordgo.Ready) {
 		fmt.Println("Bot ready!")
@@ -61,9 +64,23 @@ func (a *App) RunUntilInterupt() {
 func (a *App) HandleMessage(sess *discordgo.Session, m *discordgo.MessageCreate) {
 
 	// Ignoring self, very important
-	if m.Member.User.ID == sess.State.User.ID {
+	if m.Author.ID == sess.State.User.ID {
 		return
 	}
+
+	if m.Content != commandPrefix && !strings.HasPrefix(m.Content, commandPrefix+" ") {
+		return
+	}
+
+	reply, err := a.handleClanCommand(a.ctx, sess, m)
+	if err != nil {
+		fmt.Printf("Error handling command: %s\n", err.Error())
+		reply = "Something went wrong, try again later"
+	}
+
+	if _, err := sess.ChannelMessageSend(m.ChannelID, reply); err != nil {
+		fmt.Printf("Error sending message: %s\n", err.Error())
+	}
 }
 
 func (a *App) getGuildConfig(g *discordgo.Guild) (Guild, error) {
@@ -77,3 +94,28 @@ func (a *App) getGuildConfig(g *discordgo.Guild) (Guild, error) {
 
 	return dbGuild, nil
 }
+
+// LinkGuildToClan links the guild with the provided ID to the clan with the provided name and returns
+// the clan
+func (a *App) LinkGuildToClan(ctx context.Context, guildID uint64, name string) (destiny2.GroupV2, error) {
+	group, err := a.d2Client.GroupV2Service.GetGroupByName(ctx, name, destiny2.GroupTypeClan)
+	if err != nil {
+		return destiny2.GroupV2{}, err
+	}
+
+	if err := a.repo.SetGuildGroupID(ctx, guildID, &group.Detail.GroupID); err != nil {
+		return destiny2.GroupV2{}, err
+	}
+
+	return group.Detail, nil
+}
+
+// IsClanMember checks if the user is a member of the clan the guild is linked to. If the guild is not
+// linked to a clan false is returned
+func (a *App) IsClanMember(ctx context.Context, guild Guild, user User) (bool, error) {
+	if guild.GroupID == nil {
+		return false, nil
+	}
+
+	return a.d2Client.GroupV2Service.IsMemberOfGroup(ctx, user.MembershipType, user.MembershipID, *guild.GroupID)
+}
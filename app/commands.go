@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/duke605/zavala/destiny2"
+)
+
+// commandPrefix prefixes every message the bot responds to
+const commandPrefix = "!clan"
+
+// handleClanCommand runs the clan command in the message. The command is either "link <clan name>",
+// which links the guild to a clan and can only be used by members that can manage the guild, or "check",
+// which checks if the author is a member of the guild's clan
+func (a *App) handleClanCommand(ctx context.Context, sess *discordgo.Session, m *discordgo.MessageCreate) (string, error) {
+	guildID, err := strconv.ParseUint(m.GuildID, 10, 64)
+	if err != nil {
+		return "Clan commands can only be used in a server", nil
+	}
+
+	args := strings.Fields(strings.TrimPrefix(m.Content, commandPrefix))
+	if len(args) == 0 {
+		return fmt.Sprintf("Usage: %s link <clan name> | %s check", commandPrefix, commandPrefix), nil
+	}
+
+	switch args[0] {
+	case "link":
+		perms, err := sess.UserChannelPermissions(m.Author.ID, m.ChannelID)
+		if err != nil {
+			return "", err
+		}
+		if perms&discordgo.PermissionManageServer == 0 {
+			return "Only members that can manage the server can link it to a clan", nil
+		}
+
+		name := strings.Join(args[1:], " ")
+		if name == "" {
+			return fmt.Sprintf("Usage: %s link <clan name>", commandPrefix), nil
+		}
+
+		group, err := a.LinkGuildToClan(ctx, guildID, name)
+		var apiErr *destiny2.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode == destiny2.CodeGroupNotFound {
+			return fmt.Sprintf("Could not find a clan named %s", name), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("Linked this server to %s (level %d)", group.Name, group.ClanLevel()), nil
+
+	case "check":
+		guild, err := a.repo.GetGuildByID(ctx, guildID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+
+		userID, _ := strconv.ParseUint(m.Author.ID, 10, 64)
+		user, err := a.repo.GetUserByID(ctx, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "You have not connected your Destiny 2 account", nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if guild.GroupID == nil {
+			return "This server is not linked to a clan", nil
+		}
+
+		member, err := a.IsClanMember(ctx, guild, user)
+		if err != nil {
+			return "", err
+		}
+		if !member {
+			return "You are not a member of this server's clan", nil
+		}
+
+		return "You are a member of this server's clan", nil
+	}
+
+	return fmt.Sprintf("Unknown command %s", args[0]), nil
+}
@@ -65,7 +65,6 @@ func (a *App) DeleteRemovedGuilds(ctx context.Context) error {
 func (a *App) SetNicknames(ctx context.Context) error {
 	guilds := a.bot.State.Guilds
 	wg := &sync.WaitGroup{}
-	config := a.d2Client.GetOAuthConfig()
 
 	// Sets the nickname of every member (if applicable) for the provided guild
 	setNicksForGuild := func(guild *discordgo.Guild) {
@@ -87,32 +86,29 @@ func (a *App) SetNicknames(ctx context.Context) error {
 				continue
 			}
 
-			// Creasting token source instead of client so we can see if the token has changed
-			t := user.Token()
-			ts := config.TokenSource(ctx, t)
-			tNew, err := ts.Token()
+			// Getting the user's linked profiles. The endpoint is public so it works even if the
+			// user's token has expired
+			linked, err := a.d2Client.Destiny2Service.GetLinkedProfiles(ctx, user.MembershipType, user.MembershipID)
 			if err != nil {
-				fmt.Printf("Failed to refresh token for user '%d': %s\n", user.ID, err.Error())
-				continue
-			}
+				fmt.Printf("Error getting linked profiles: %s\n", err.Error())
+
+				// Every other request for this guild will fail the same way so we stop and wait
+				// for the next run of the job
+				if destiny2.IsMaintenance(err) || destiny2.IsThrottled(err) {
+					return
+				}
 
-			// Getting destiny user data
-			dUser, err := a.d2Client.UserService.GetMembershipDataForCurrentUser(destiny2.OptionOAuthToken(tNew))
-			fmt.Println(dUser)
-			if err != nil {
-				fmt.Printf("Error getting user data: %s\n", err.Error())
 				continue
 			}
 
 			// Finding active destiny 2 account
-			for _, d2Acc := range dUser.DestinyMemberships {
-				if d2Acc.CrossSaveOverride == d2Acc.MembershipType {
-					if err := a.bot.GuildMemberNickname(guild.ID, member.User.ID, d2Acc.DisplayName); err != nil {
-						fmt.Printf("Error changing nickname: %s\n", err.Error())
-					}
+			active, ok := user.ActiveMembership(linked)
+			if !ok {
+				continue
+			}
 
-					break
-				}
+			if err := a.bot.GuildMemberNickname(guild.ID, member.User.ID, active.DisplayName); err != nil {
+				fmt.Printf("Error changing nickname: %s\n", err.Error())
 			}
 		}
 	}
@@ -6,10 +6,15 @@ import (
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/duke605/zavala/destiny2"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/oauth2"
 )
 
+// maxOpenConns is the most connections the repo opens to the database. Token refreshes hold a connection
+// while they wait on Bungie so there must be spare connections for everything else
+const maxOpenConns = 10
+
 // Repo is used to interact with the application's datastore
 type Repo struct {
 	db *sqlx.DB
@@ -22,7 +27,7 @@ func NewRepo(driver, dsn string) *Repo {
 	if err != nil {
 		panic(err)
 	}
-	db.SetMaxOpenConns(1)
+	db.SetMaxOpenConns(maxOpenConns)
 
 	return &Repo{
 		db: db,
@@ -37,12 +42,12 @@ type Guild struct {
 
 // User represets a discord user that has connected their Destiny 2 account
 type User struct {
-	ID             uint64    `db:"id"`
-	MembershipType int       `db:"membership_type"`
-	MembershipID   int64     `db:"membership_id"`
-	AccessToken    string    `db:"access_token"`
-	RefreshToken   string    `db:"refresh_token"`
-	Expiry         time.Time `db:"expiry"`
+	ID             uint64                        `db:"id"`
+	MembershipType destiny2.BungieMembershipType `db:"membership_type"`
+	MembershipID   int64                         `db:"membership_id"`
+	AccessToken    string                        `db:"access_token"`
+	RefreshToken   string                        `db:"refresh_token"`
+	Expiry         time.Time                     `db:"expiry"`
 }
 
 // Token creates and returns an oauth2.Token for the user
@@ -55,13 +60,30 @@ func (u *User) Token() *oauth2.Token {
 	}
 }
 
+// ActiveMembership returns the profile the user is playing Destiny 2 on from their linked profiles. If it
+// can't be determined because the user has multiple accounts and no cross save, the profile they registered
+// with is returned instead
+func (u *User) ActiveMembership(linked destiny2.DestinyLinkedProfilesResponse) (destiny2.UserInfoCard, bool) {
+	if active, ok := linked.PrimaryMembership(); ok {
+		return active, true
+	}
+
+	for _, profile := range linked.Profiles {
+		if profile.MembershipID == u.MembershipID {
+			return profile.UserInfoCard(), true
+		}
+	}
+
+	return destiny2.UserInfoCard{}, false
+}
+
 // Transaction begins a transaction and calls the provided function. The context passed to fn should be passed
 // to all function calls made to repo to ensure that they are made against the database transaction and not the
 // database itself. Any errors that occur in fn should be returned so that the transaction is rolled back instead
 // of commited
 func (r Repo) Transaction(ctx context.Context, fn func(context.Context) error) error {
 	ctx = ensureContext(ctx)
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -71,20 +93,20 @@ func (r Repo) Transaction(ctx context.Context, fn func(context.Context) error) e
 			// Recovering from panic to rollback the transaction before panicing again
 			tx.Rollback()
 			panic(p)
-		} else if err != nil {
-			// Something went wrong in fn, rolling back
-			tx.Rollback()
-		} else {
-			// Nothing when wrong, commiting transaction
-			tx.Commit()
 		}
 	}()
 
 	// Storing transaction in context
 	ctx = context.WithValue(ctx, transaction, tx)
 
-	err = fn(ctx)
-	return err
+	if err = fn(ctx); err != nil {
+		// Something went wrong in fn, rolling back
+		tx.Rollback()
+		return err
+	}
+
+	// Nothing when wrong, commiting transaction
+	return tx.Commit()
 }
 
 // SyncGuilds deletes guilds from the database that do not appear in the provided guildIDs array
@@ -123,6 +145,24 @@ func (r *Repo) GetGuildByID(ctx context.Context, gid uint64) (Guild, error) {
 	return g, err
 }
 
+// SetGuildGroupID sets the ID of the clan a guild is linked to. A nil groupID unlinks the guild from
+// its clan. The guild is added to the DB if it is not in it yet
+func (r *Repo) SetGuildGroupID(ctx context.Context, gid uint64, groupID *int64) error {
+	ctx = ensureContext(ctx)
+	sql, args, err := sq.Insert("guilds").
+		Columns("id", "group_id").
+		Values(gid, groupID).
+		Suffix("ON DUPLICATE KEY UPDATE group_id = VALUES(group_id)").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	execer := execerFromContext(ctx, r.db)
+	_, err = execer.ExecContext(ctx, sql, args...)
+	return err
+}
+
 // GetUserByID gets a user from the DB by their DB.
 //
 // If the user is not found in the DB sql.ErrNoRows will be returned
@@ -165,6 +205,78 @@ func (r *Repo) GetUserByMembershipID(ctx context.Context, id int64) (User, error
 	return u, err
 }
 
+// LoadToken loads the OAuth token of the user with the provided destiny 2 membership ID. Implements
+// destiny2.TokenStore
+//
+// If the user is not found in the DB sql.ErrNoRows will be returned
+func (r *Repo) LoadToken(ctx context.Context, membershipID int64) (*oauth2.Token, error) {
+	u, err := r.GetUserByMembershipID(ctx, membershipID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Token(), nil
+}
+
+// SaveToken saves the OAuth token of the user with the provided destiny 2 membership ID. Implements
+// destiny2.TokenStore
+func (r *Repo) SaveToken(ctx context.Context, membershipID int64, t *oauth2.Token) error {
+	ctx = ensureContext(ctx)
+	sql, args, err := sq.Update("users").SetMap(sq.Eq{
+		"access_token":  t.AccessToken,
+		"refresh_token": t.RefreshToken,
+		"expiry":        t.Expiry,
+	}).Where(sq.Eq{
+		"membership_id": membershipID,
+	}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	execer := execerFromContext(ctx, r.db)
+	_, err = execer.ExecContext(ctx, sql, args...)
+	return err
+}
+
+// WithTokenLock locks the row of the user with the provided destiny 2 membership ID and calls fn with the
+// user's current token. The token returned by fn is saved before the lock is released. Implements
+// destiny2.LockingTokenStore
+func (r *Repo) WithTokenLock(ctx context.Context, membershipID int64, fn func(*oauth2.Token) (*oauth2.Token, error)) (*oauth2.Token, error) {
+	var t *oauth2.Token
+	err := r.Transaction(ctx, func(ctx context.Context) error {
+		u, err := r.lockUserByMembershipID(ctx, membershipID)
+		if err != nil {
+			return err
+		}
+
+		if t, err = fn(u.Token()); err != nil {
+			return err
+		}
+
+		return r.SaveToken(ctx, membershipID, t)
+	})
+
+	return t, err
+}
+
+// lockUserByMembershipID gets a user from the DB by their destiny 2 membership ID and locks their row until
+// the transaction in ctx ends.
+//
+// If the user is not found in the DB sql.ErrNoRows will be returned
+func (r *Repo) lockUserByMembershipID(ctx context.Context, id int64) (User, error) {
+	u := User{}
+	sql, args, err := sq.Select("*").From("users").Where(sq.Eq{
+		"membership_id": id,
+	}).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return u, err
+	}
+
+	execer := execerFromContext(ctx, r.db)
+	err = execer.GetContext(ctx, &u, sql, args...)
+	return u, err
+}
+
 // key is used to store values in context and retrieve them
 type key int
 
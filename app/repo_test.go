@@ -0,0 +1,292 @@
+package app_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/app"
+	"github.com/duke605/zavala/destiny2"
+	"golang.org/x/oauth2"
+)
+
+func TestUserActiveMembership(t *testing.T) {
+	xbox := destiny2.DestinyProfileUserInfoCard{MembershipType: destiny2.MembershipTypeXbox, MembershipID: 1, DisplayName: "Xbox"}
+	steam := destiny2.DestinyProfileUserInfoCard{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 3, DisplayName: "Steam"}
+	primary := steam
+	primary.IsCrossSavePrimary = true
+
+	tests := map[string]struct {
+		registered int64
+		profiles   []destiny2.DestinyProfileUserInfoCard
+		want       string
+		ok         bool
+	}{
+		"single profile":              {1, []destiny2.DestinyProfileUserInfoCard{xbox}, "Xbox", true},
+		"cross save primary":          {1, []destiny2.DestinyProfileUserInfoCard{xbox, primary}, "Steam", true},
+		"falls back to registered":    {3, []destiny2.DestinyProfileUserInfoCard{xbox, steam}, "Steam", true},
+		"registered profile unlinked": {2, []destiny2.DestinyProfileUserInfoCard{xbox, steam}, "", false},
+		"no profiles":                 {1, nil, "", false},
+	}
+	for name, tt := range tests {
+		user := app.User{MembershipID: tt.registered}
+		got, ok := user.ActiveMembership(destiny2.DestinyLinkedProfilesResponse{Profiles: tt.profiles})
+		if ok != tt.ok || got.DisplayName != tt.want {
+			t.Errorf("%s: ActiveMembership() = %q, %t, want %q, %t", name, got.DisplayName, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTransactionCommits(t *testing.T) {
+	db := newFakeDB(t)
+	repo := app.NewRepo("fakedb", t.Name())
+
+	err := repo.Transaction(context.Background(), func(ctx context.Context) error {
+		return repo.SaveToken(ctx, 1234, &oauth2.Token{AccessToken: "access"})
+	})
+	if err != nil {
+		t.Fatalf("Transaction() error = %v", err)
+	}
+	db.expect(t, "begin", "exec UPDATE users", "commit")
+}
+
+func TestTransactionRollsBack(t *testing.T) {
+	db := newFakeDB(t)
+	repo := app.NewRepo("fakedb", t.Name())
+
+	want := errors.New("failed")
+	err := repo.Transaction(context.Background(), func(ctx context.Context) error {
+		return want
+	})
+	if err != want {
+		t.Fatalf("Transaction() error = %v, want %v", err, want)
+	}
+	db.expect(t, "begin", "rollback")
+}
+
+func TestTransactionReturnsCommitError(t *testing.T) {
+	db := newFakeDB(t)
+	db.commitErr = errors.New("commit failed")
+	repo := app.NewRepo("fakedb", t.Name())
+
+	err := repo.Transaction(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+	if err != db.commitErr {
+		t.Errorf("Transaction() error = %v, want %v", err, db.commitErr)
+	}
+}
+
+func TestSetGuildGroupIDWithoutRow(t *testing.T) {
+	db := newFakeDB(t)
+	repo := app.NewRepo("fakedb", t.Name())
+
+	// The fake database has no guild rows so the guild must be inserted for the link to be saved
+	groupID := int64(42)
+	if err := repo.SetGuildGroupID(context.Background(), 1, &groupID); err != nil {
+		t.Fatalf("SetGuildGroupID() error = %v", err)
+	}
+	db.expect(t, "exec INSERT INTO guilds (id,group_id) VALUES (?,?) ON DUPLICATE KEY UPDATE group_id = VALUES(group_id) [1 42]")
+}
+
+func TestWithTokenLock(t *testing.T) {
+	db := newFakeDB(t)
+	repo := app.NewRepo("fakedb", t.Name())
+
+	refreshed := &oauth2.Token{AccessToken: "refreshed"}
+	got, err := repo.WithTokenLock(context.Background(), 1234, func(current *oauth2.Token) (*oauth2.Token, error) {
+		if current.AccessToken != "access" {
+			t.Errorf("WithTokenLock() current token = %q, want %q", current.AccessToken, "access")
+		}
+		return refreshed, nil
+	})
+	if err != nil {
+		t.Fatalf("WithTokenLock() error = %v", err)
+	}
+	if got != refreshed {
+		t.Errorf("WithTokenLock() = %v, want the refreshed token", got)
+	}
+	db.expect(t, "begin", "query SELECT * FROM users WHERE membership_id = ? FOR UPDATE", "exec UPDATE users", "commit")
+}
+
+func TestWithTokenLockFailedRefresh(t *testing.T) {
+	db := newFakeDB(t)
+	repo := app.NewRepo("fakedb", t.Name())
+
+	want := errors.New("refresh failed")
+	_, err := repo.WithTokenLock(context.Background(), 1234, func(current *oauth2.Token) (*oauth2.Token, error) {
+		return nil, want
+	})
+	if err != want {
+		t.Fatalf("WithTokenLock() error = %v, want %v", err, want)
+	}
+	db.expect(t, "begin", "query SELECT * FROM users WHERE membership_id = ? FOR UPDATE", "rollback")
+}
+
+func TestWithTokenLockLeavesConnections(t *testing.T) {
+	newFakeDB(t)
+	repo := app.NewRepo("fakedb", t.Name())
+
+	// Holding the lock like a slow refresh would
+	locked, release := make(chan struct{}), make(chan struct{})
+	go repo.WithTokenLock(context.Background(), 1234, func(current *oauth2.Token) (*oauth2.Token, error) {
+		close(locked)
+		<-release
+		return current, nil
+	})
+	defer close(release)
+	<-locked
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.GetUserByID(context.Background(), 1)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("GetUserByID() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("GetUserByID() blocked while a token was locked")
+	}
+}
+
+func init() {
+	sql.Register("fakedb", fakeDriver{})
+}
+
+// fakeDBs holds the fake databases by their DSN
+var fakeDBs = sync.Map{}
+
+// fakeDB logs the statements it is sent and answers every query with the same user
+type fakeDB struct {
+	mu        sync.Mutex
+	log       []string
+	commitErr error
+}
+
+// newFakeDB creates a fake database that can be opened using the name of the test as the DSN
+func newFakeDB(t *testing.T) *fakeDB {
+	db := &fakeDB{}
+	fakeDBs.Store(t.Name(), db)
+	return db
+}
+
+func (db *fakeDB) record(entry string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.log = append(db.log, entry)
+}
+
+// expect checks that the database was sent statements starting with the provided prefixes in order
+func (db *fakeDB) expect(t *testing.T, prefixes ...string) {
+	t.Helper()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if len(db.log) != len(prefixes) {
+		t.Fatalf("database log = %q, want %q", db.log, prefixes)
+	}
+	for i, prefix := range prefixes {
+		if !strings.HasPrefix(db.log[i], prefix) {
+			t.Errorf("database log[%d] = %q, want prefix %q", i, db.log[i], prefix)
+		}
+	}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	db, ok := fakeDBs.Load(dsn)
+	if !ok {
+		return nil, errors.New("fakedb: unknown database " + dsn)
+	}
+
+	return &fakeConn{db.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c.db, query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.record("begin")
+	return &fakeTx{c.db}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.db.record("commit")
+	return tx.db.commitErr
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.record("rollback")
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.record(fmt.Sprintf("exec %s %v", s.query, args))
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.record("query " + s.query)
+	return &fakeRows{}, nil
+}
+
+// fakeRows is a single row holding a user
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id", "membership_type", "membership_id", "access_token", "refresh_token", "expiry"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+
+	copy(dest, []driver.Value{int64(1), int64(3), int64(1234), "access", "refresh", time.Now().Add(time.Hour)})
+	return nil
+}
@@ -0,0 +1,331 @@
+package destiny2
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DestinyActivityModeType ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-Definitions-DestinyActivityModeType.html#schema_Destiny-HistoricalStats-Definitions-DestinyActivityModeType
+type DestinyActivityModeType int
+
+// DestinyActivityModeType values
+const (
+	ModeNone                    DestinyActivityModeType = 0
+	ModeStory                   DestinyActivityModeType = 2
+	ModeStrike                  DestinyActivityModeType = 3
+	ModeRaid                    DestinyActivityModeType = 4
+	ModeAllPvP                  DestinyActivityModeType = 5
+	ModePatrol                  DestinyActivityModeType = 6
+	ModeAllPvE                  DestinyActivityModeType = 7
+	ModeControl                 DestinyActivityModeType = 10
+	ModeClash                   DestinyActivityModeType = 12
+	ModeCrimsonDoubles          DestinyActivityModeType = 15
+	ModeNightfall               DestinyActivityModeType = 16
+	ModeHeroicNightfall         DestinyActivityModeType = 17
+	ModeAllStrikes              DestinyActivityModeType = 18
+	ModeIronBanner              DestinyActivityModeType = 19
+	ModeAllMayhem               DestinyActivityModeType = 25
+	ModeSupremacy               DestinyActivityModeType = 31
+	ModePrivateMatchesAll       DestinyActivityModeType = 32
+	ModeSurvival                DestinyActivityModeType = 37
+	ModeCountdown               DestinyActivityModeType = 38
+	ModeTrialsOfTheNine         DestinyActivityModeType = 39
+	ModeSocial                  DestinyActivityModeType = 40
+	ModeTrialsCountdown         DestinyActivityModeType = 41
+	ModeTrialsSurvival          DestinyActivityModeType = 42
+	ModeIronBannerControl       DestinyActivityModeType = 43
+	ModeIronBannerClash         DestinyActivityModeType = 44
+	ModeIronBannerSupremacy     DestinyActivityModeType = 45
+	ModeScoredNightfall         DestinyActivityModeType = 46
+	ModeScoredHeroicNightfall   DestinyActivityModeType = 47
+	ModeRumble                  DestinyActivityModeType = 48
+	ModeAllDoubles              DestinyActivityModeType = 49
+	ModeDoubles                 DestinyActivityModeType = 50
+	ModePrivateMatchesClash     DestinyActivityModeType = 51
+	ModePrivateMatchesControl   DestinyActivityModeType = 52
+	ModePrivateMatchesSupremacy DestinyActivityModeType = 53
+	ModePrivateMatchesCountdown DestinyActivityModeType = 54
+	ModePrivateMatchesSurvival  DestinyActivityModeType = 55
+	ModePrivateMatchesMayhem    DestinyActivityModeType = 56
+	ModePrivateMatchesRumble    DestinyActivityModeType = 57
+	ModeHeroicAdventure         DestinyActivityModeType = 58
+	ModeShowdown                DestinyActivityModeType = 59
+	ModeLockdown                DestinyActivityModeType = 60
+	ModeScorched                DestinyActivityModeType = 61
+	ModeScorchedTeam            DestinyActivityModeType = 62
+	ModeGambit                  DestinyActivityModeType = 63
+	ModeAllPvECompetitive       DestinyActivityModeType = 64
+	ModeBreakthrough            DestinyActivityModeType = 65
+	ModeBlackArmoryRun          DestinyActivityModeType = 66
+	ModeSalvage                 DestinyActivityModeType = 67
+	ModeIronBannerSalvage       DestinyActivityModeType = 68
+	ModePvPCompetitive          DestinyActivityModeType = 69
+	ModePvPQuickplay            DestinyActivityModeType = 70
+	ModeClashQuickplay          DestinyActivityModeType = 71
+	ModeClashCompetitive        DestinyActivityModeType = 72
+	ModeControlQuickplay        DestinyActivityModeType = 73
+	ModeControlCompetitive      DestinyActivityModeType = 74
+	ModeGambitPrime             DestinyActivityModeType = 75
+	ModeReckoning               DestinyActivityModeType = 76
+	ModeMenagerie               DestinyActivityModeType = 77
+	ModeVexOffensive            DestinyActivityModeType = 78
+	ModeNightmareHunt           DestinyActivityModeType = 79
+	ModeElimination             DestinyActivityModeType = 80
+	ModeMomentum                DestinyActivityModeType = 81
+	ModeDungeon                 DestinyActivityModeType = 82
+	ModeSundial                 DestinyActivityModeType = 83
+	ModeTrialsOfOsiris          DestinyActivityModeType = 84
+	ModeDares                   DestinyActivityModeType = 85
+	ModeOffensive               DestinyActivityModeType = 86
+	ModeLostSector              DestinyActivityModeType = 87
+	ModeRift                    DestinyActivityModeType = 88
+	ModeZoneControl             DestinyActivityModeType = 89
+	ModeIronBannerRift          DestinyActivityModeType = 90
+)
+
+// DestinyActivityHistoryResults ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyActivityHistoryResults.html#schema_Destiny-HistoricalStats-DestinyActivityHistoryResults
+type DestinyActivityHistoryResults struct {
+	Activities []DestinyHistoricalStatsPeriodGroup `json:"activities"`
+}
+
+// DestinyHistoricalStatsPeriodGroup ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsPeriodGroup.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsPeriodGroup
+type DestinyHistoricalStatsPeriodGroup struct {
+	Period          time.Time                      `json:"period"`
+	ActivityDetails DestinyHistoricalStatsActivity `json:"activityDetails"`
+	Values          DestinyHistoricalStatsValues   `json:"values"`
+}
+
+// Completed returns true if the character completed the activity
+func (g DestinyHistoricalStatsPeriodGroup) Completed() bool {
+	return g.Values.Basic("completed") == 1
+}
+
+// DestinyHistoricalStatsActivity ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsActivity.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsActivity
+type DestinyHistoricalStatsActivity struct {
+	ReferenceID          uint                      `json:"referenceId"`
+	DirectorActivityHash uint                      `json:"directorActivityHash"`
+	InstanceID           int64                     `json:"instanceId,string"`
+	Mode                 DestinyActivityModeType   `json:"mode"`
+	Modes                []DestinyActivityModeType `json:"modes"`
+	IsPrivate            bool                      `json:"isPrivate"`
+	MembershipType       BungieMembershipType      `json:"membershipType"`
+}
+
+// DestinyHistoricalStatsValues maps stat IDs to their values
+type DestinyHistoricalStatsValues map[string]DestinyHistoricalStatsValue
+
+// Basic returns the basic value of the stat with the provided ID or 0 if the stat is not present
+func (v DestinyHistoricalStatsValues) Basic(statID string) float64 {
+	return v[statID].Basic.Value
+}
+
+// PerGame returns the per game average of the stat with the provided ID or 0 if the stat is not
+// present or has no average
+func (v DestinyHistoricalStatsValues) PerGame(statID string) float64 {
+	if pga := v[statID].Pga; pga != nil {
+		return pga.Value
+	}
+
+	return 0
+}
+
+// DestinyHistoricalStatsValue ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsValue.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsValue
+type DestinyHistoricalStatsValue struct {
+	StatID     string                           `json:"statId"`
+	Basic      DestinyHistoricalStatsValuePair  `json:"basic"`
+	Pga        *DestinyHistoricalStatsValuePair `json:"pga"`
+	Weighted   *DestinyHistoricalStatsValuePair `json:"weighted"`
+	ActivityID *int64                           `json:"activityId,string"`
+}
+
+// DestinyHistoricalStatsValuePair ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsValuePair.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsValuePair
+type DestinyHistoricalStatsValuePair struct {
+	Value        float64 `json:"value"`
+	DisplayValue string  `json:"displayValue"`
+}
+
+// DestinyPostGameCarnageReportData ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportData.html#schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportData
+type DestinyPostGameCarnageReportData struct {
+	Period                          time.Time                               `json:"period"`
+	StartingPhaseIndex              *int                                    `json:"startingPhaseIndex"`
+	ActivityWasStartedFromBeginning bool                                    `json:"activityWasStartedFromBeginning"`
+	ActivityDetails                 DestinyHistoricalStatsActivity          `json:"activityDetails"`
+	Entries                         []DestinyPostGameCarnageReportEntry     `json:"entries"`
+	Teams                           []DestinyPostGameCarnageReportTeamEntry `json:"teams"`
+}
+
+// DestinyPostGameCarnageReportEntry ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportEntry.html#schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportEntry
+type DestinyPostGameCarnageReportEntry struct {
+	Standing    int                                       `json:"standing"`
+	Score       DestinyHistoricalStatsValue               `json:"score"`
+	Player      DestinyPlayer                             `json:"player"`
+	CharacterID int64                                     `json:"characterId,string"`
+	Values      DestinyHistoricalStatsValues              `json:"values"`
+	Extended    *DestinyPostGameCarnageReportExtendedData `json:"extended"`
+}
+
+// Completed returns true if the player completed the activity
+func (e DestinyPostGameCarnageReportEntry) Completed() bool {
+	return e.Values.Basic("completed") == 1
+}
+
+// DestinyPlayer ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyPlayer.html#schema_Destiny-HistoricalStats-DestinyPlayer
+type DestinyPlayer struct {
+	DestinyUserInfo   UserInfoCard `json:"destinyUserInfo"`
+	CharacterClass    string       `json:"characterClass"`
+	ClassHash         uint         `json:"classHash"`
+	RaceHash          uint         `json:"raceHash"`
+	GenderHash        uint         `json:"genderHash"`
+	CharacterLevel    int          `json:"characterLevel"`
+	LightLevel        int          `json:"lightLevel"`
+	BungieNetUserInfo UserInfoCard `json:"bungieNetUserInfo"`
+	ClanName          string       `json:"clanName"`
+	ClanTag           string       `json:"clanTag"`
+	EmblemHash        uint         `json:"emblemHash"`
+}
+
+// DestinyPostGameCarnageReportExtendedData ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportExtendedData.html#schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportExtendedData
+type DestinyPostGameCarnageReportExtendedData struct {
+	Weapons []DestinyHistoricalWeaponStats `json:"weapons"`
+	Values  DestinyHistoricalStatsValues   `json:"values"`
+}
+
+// DestinyHistoricalWeaponStats ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalWeaponStats.html#schema_Destiny-HistoricalStats-DestinyHistoricalWeaponStats
+type DestinyHistoricalWeaponStats struct {
+	ReferenceID uint                         `json:"referenceId"`
+	Values      DestinyHistoricalStatsValues `json:"values"`
+}
+
+// DestinyPostGameCarnageReportTeamEntry ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportTeamEntry.html#schema_Destiny-HistoricalStats-DestinyPostGameCarnageReportTeamEntry
+type DestinyPostGameCarnageReportTeamEntry struct {
+	TeamID   int                         `json:"teamId"`
+	Standing DestinyHistoricalStatsValue `json:"standing"`
+	Score    DestinyHistoricalStatsValue `json:"score"`
+	TeamName string                      `json:"teamName"`
+}
+
+// GetActivityHistory gets a page of the activities the given character has played, most recent first.
+// Pages start at 0 and hold count activities, up to 250. If count is 0 the API's default is used.
+// Use ModeNone to include activities of every mode. An empty page is returned once there are no more
+// activities
+func (ds *Destiny2Service) GetActivityHistory(ctx context.Context, membershipType BungieMembershipType, membershipID, characterID int64, mode DestinyActivityModeType, page, count int, opts ...RequestOption) (DestinyActivityHistoryResults, error) {
+	r := DestinyActivityHistoryResults{}
+	endpoint := fmt.Sprintf("/%d/Account/%d/Character/%d/Stats/Activities", membershipType, membershipID, characterID)
+	query := []RequestOption{OptionQuery("mode", int(mode)), OptionQuery("page", page)}
+	if count > 0 {
+		query = append(query, OptionQuery("count", count))
+	}
+
+	err := ds.do(ctx, "GET", endpoint, &r, append(query, opts...)...)
+	return r, err
+}
+
+// GetPostGameCarnageReport gets the post game carnage report of the activity with the provided instance ID.
+// Reports are only served by the stats host so the request is sent to the client's stats site
+func (ds *Destiny2Service) GetPostGameCarnageReport(ctx context.Context, activityID int64, opts ...RequestOption) (DestinyPostGameCarnageReportData, error) {
+	r := DestinyPostGameCarnageReportData{}
+	endpoint := fmt.Sprintf("/Stats/PostGameCarnageReport/%d", activityID)
+	opts = append([]RequestOption{OptionSiteURL(ds.c.statsSiteURL)}, opts...)
+	err := ds.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// DefaultActivityHistoryPageSize is the number of activities the API returns per page of activity history
+// when a count is not provided
+const DefaultActivityHistoryPageSize = 25
+
+// ActivityHistoryPager steps through the activity history of a character one page at a time, most recent
+// first. Activity history can't be paged with Pager since its pages start at 0, are sized with count
+// instead of itemsPerPage and come without a SearchResult, so the last page is only known once a page
+// comes back with fewer activities than were asked for. It is used the same way as Pager.
+//
+//	p := client.Destiny2Service.NewActivityHistoryPager(membershipType, membershipID, characterID, destiny2.ModeRaid, 250)
+//	for p.Next(ctx) {
+//		// Use p.Activities()
+//	}
+//	if err := p.Err(); err != nil {
+//		// Handle error
+//	}
+type ActivityHistoryPager struct {
+	ds             *Destiny2Service
+	membershipType BungieMembershipType
+	membershipID   int64
+	characterID    int64
+	mode           DestinyActivityModeType
+	count          int
+	opts           []RequestOption
+	page           int
+	activities     []DestinyHistoricalStatsPeriodGroup
+	done           bool
+	err            error
+}
+
+// NewActivityHistoryPager creates a pager over the activities of the given character. count is the number
+// of activities fetched per page, up to 250. DefaultActivityHistoryPageSize is used if count is 0. Any
+// options provided are passed to GetActivityHistory for every page
+func (ds *Destiny2Service) NewActivityHistoryPager(membershipType BungieMembershipType, membershipID, characterID int64, mode DestinyActivityModeType, count int, opts ...RequestOption) *ActivityHistoryPager {
+	if count <= 0 {
+		count = DefaultActivityHistoryPageSize
+	}
+
+	return &ActivityHistoryPager{
+		ds:             ds,
+		membershipType: membershipType,
+		membershipID:   membershipID,
+		characterID:    characterID,
+		mode:           mode,
+		count:          count,
+		opts:           opts,
+		page:           -1,
+	}
+}
+
+// Next fetches the next page. False is returned when there are no activities left or an error occurred,
+// in which case the error can be retrieved with ActivityHistoryPager.Err
+func (p *ActivityHistoryPager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	p.page++
+
+	r, err := p.ds.GetActivityHistory(ctx, p.membershipType, p.membershipID, p.characterID, p.mode, p.page, p.count, p.opts...)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	p.activities = r.Activities
+	p.done = len(r.Activities) < p.count
+
+	return len(r.Activities) > 0
+}
+
+// Activities returns the activities of the page last fetched by ActivityHistoryPager.Next
+func (p *ActivityHistoryPager) Activities() []DestinyHistoricalStatsPeriodGroup {
+	return p.activities
+}
+
+// Page returns the number of the page last fetched by ActivityHistoryPager.Next. Pages start at 0 like
+// they do for GetActivityHistory
+func (p *ActivityHistoryPager) Page() int {
+	return p.page
+}
+
+// Err returns the error that stopped the pager, if any
+func (p *ActivityHistoryPager) Err() error {
+	return p.err
+}
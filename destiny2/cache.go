@@ -0,0 +1,232 @@
+package destiny2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache stores responses from public Bungie API endpoints. Implementations must be safe to use
+// from multiple go routines
+type Cache interface {
+
+	// Get returns the entry stored under key
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores entry under key
+	Set(key string, entry *CacheEntry)
+}
+
+// CacheEntry is a cached response
+type CacheEntry struct {
+	Response Response  `json:"response"`
+	ETag     string    `json:"etag"`
+	Expires  time.Time `json:"expires"`
+}
+
+// fresh returns true if the entry can be used without asking Bungie if it has changed
+func (e *CacheEntry) fresh() bool {
+	return time.Now().Before(e.Expires)
+}
+
+// response returns a copy of the cached response that is safe to hand to callers
+func (e *CacheEntry) response() *Response {
+	r := e.Response
+	r.Header = r.Header.Clone()
+	return &r
+}
+
+// MemoryCache is a Cache that keeps a fixed number of responses in memory, evicting the least
+// recently used response when it is full
+type MemoryCache struct {
+	entries *lru
+}
+
+// NewMemoryCache creates a memory cache that holds up to size responses
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{newLRU(size)}
+}
+
+// Get implements Cache
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	e, ok := c.entries.get(key)
+	if !ok {
+		return nil, false
+	}
+
+	return e.(*CacheEntry), true
+}
+
+// Set implements Cache
+func (c *MemoryCache) Set(key string, entry *CacheEntry) {
+	c.entries.add(key, entry)
+}
+
+// DiskCache is a Cache that stores every response as a file in a directory so the cache survives restarts
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a disk cache that stores responses in dir. The directory is created if it does
+// not exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &DiskCache{dir}, nil
+}
+
+// path returns the path of the file the entry for key is stored in
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get implements Cache
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	e := &CacheEntry{}
+	if err = json.Unmarshal(b, e); err != nil {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// Set implements Cache. Errors writing the entry are ignored since a missed entry only costs a request
+func (c *DiskCache) Set(key string, entry *CacheEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Writing to a temp file and moving it into place so readers never see a partial entry
+	tmp, err := ioutil.TempFile(c.dir, "entry-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+
+	os.Rename(tmp.Name(), c.path(key))
+}
+
+// SetCache enables caching of responses from public endpoints using the provided cache. Responses are
+// cached for as long as Bungie's Cache-Control header allows and revalidated with their ETag once they
+// expire. Responses to requests made with an OAuth token are never cached. Passing nil disables the
+// cache. Function returns self for ease of chaining
+func (c *Client) SetCache(cache Cache) *Client {
+	c.cache = cache
+	return c
+}
+
+// shareable returns true if the response to req can be shared with every user of the client
+func (c *Client) shareable(req *http.Request) bool {
+	return !c.authenticated &&
+		req.Method == http.MethodGet &&
+		req.Body == nil &&
+		req.Header.Get("Authorization") == ""
+}
+
+// cacheDoer wraps next so that responses to cacheable requests are served from the client's cache
+func (c *Client) cacheDoer(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*Response, error) {
+		if !c.shareable(req) {
+			return next.Do(req)
+		}
+
+		key := req.URL.String()
+		entry, ok := c.cache.Get(key)
+		if ok && entry.fresh() {
+			return entry.response(), nil
+		}
+
+		// Asking Bungie if our stale copy is still good
+		if ok && entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+
+		resp, err := next.Do(req)
+		if ok && resp != nil && resp.StatusCode == http.StatusNotModified {
+
+			// Other go routines may be reading the cached entry so a copy is updated instead
+			revalidated := *entry
+			revalidated.Response.Header = entry.Response.Header.Clone()
+			revalidated.Expires = cacheExpiry(resp.Header)
+			c.cache.Set(key, &revalidated)
+			return revalidated.response(), nil
+		}
+		if err != nil || resp.StatusCode == http.StatusNotModified {
+			return resp, err
+		}
+
+		if header := resp.Header.Get("Cache-Control"); !cacheForbidden(header) {
+			etag := resp.Header.Get("ETag")
+			expires := cacheExpiry(resp.Header)
+			if etag != "" || expires.After(time.Now()) {
+				cached := *resp
+				cached.Header = resp.Header.Clone()
+				c.cache.Set(key, &CacheEntry{
+					Response: cached,
+					ETag:     etag,
+					Expires:  expires,
+				})
+			}
+		}
+
+		return resp, nil
+	})
+}
+
+// cacheForbidden returns true if the Cache-Control header forbids shared caches from storing the response
+func cacheForbidden(cacheControl string) bool {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-store", "private":
+			return true
+		}
+	}
+
+	return false
+}
+
+// cacheExpiry returns when a response with the provided headers should be revalidated
+func cacheExpiry(h http.Header) time.Time {
+	now := time.Now()
+
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-cache" {
+			return now
+		}
+
+		if strings.HasPrefix(directive, "max-age=") {
+			secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil {
+				return now.Add(time.Duration(secs) * time.Second)
+			}
+		}
+	}
+
+	if t, err := http.ParseTime(h.Get("Expires")); err == nil {
+		return t
+	}
+
+	return now
+}
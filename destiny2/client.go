@@ -1,14 +1,18 @@
 package destiny2
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
+	"strings"
 
 	"golang.org/x/oauth2"
 )
@@ -16,7 +20,26 @@ import (
 const (
 
 	// BaseURL is the base URL for the Bungie API.
-	BaseURL = "https://www.bungie.net/Platform"
+	//
+	// Deprecated: the client builds API URLs from SiteURL or the URL set with SetSiteURL
+	BaseURL = SiteURL + "/Platform"
+
+	// SiteURL is the URL of the Bungie website. The API, OAuth endpoints and assets are all served
+	// from paths under it
+	SiteURL = "https://www.bungie.net"
+
+	// StatsSiteURL is the URL of the Bungie site that serves endpoints only available from the stats
+	// host, like post game carnage reports
+	StatsSiteURL = "https://stats.bungie.net"
+)
+
+// key is used to store values in a request's context and retrieve them
+type key int
+
+const (
+
+	// retryPolicyKey is used to store a retry policy in a request's context
+	retryPolicyKey key = iota
 )
 
 // RequestOption can be passed to functions that accept it to modify the http request
@@ -31,7 +54,35 @@ func OptionBody(r io.ReadCloser) RequestOption {
 	}
 }
 
-// OptionContext adds a context to a request
+// OptionHeader sets a header on a request
+func OptionHeader(key, value string) RequestOption {
+	return func(req *http.Request) *http.Request {
+		req.Header.Set(key, value)
+		return req
+	}
+}
+
+// OptionJSONBody encodes v as JSON and adds it as the body of a request
+func OptionJSONBody(v interface{}) (RequestOption, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(req *http.Request) *http.Request {
+		req = OptionBody(ioutil.NopCloser(bytes.NewReader(b)))(req)
+		req.ContentLength = int64(len(b))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(b)), nil
+		}
+
+		return OptionHeader("Content-Type", "application/json")(req)
+	}, nil
+}
+
+// OptionContext adds a context to a request.
+//
+// Deprecated: every service method accepts a context which should be used instead
 func OptionContext(ctx context.Context) RequestOption {
 	return func(req *http.Request) *http.Request {
 		return req.WithContext(ctx)
@@ -49,6 +100,43 @@ func OptionQuery(key string, value interface{}) RequestOption {
 	}
 }
 
+// OptionSetQuery sets a query param on a request, replacing any values the param already has
+func OptionSetQuery(key string, value interface{}) RequestOption {
+	return func(req *http.Request) *http.Request {
+		q := req.URL.Query()
+		q.Set(key, fmt.Sprint(value))
+		req.URL.RawQuery = q.Encode()
+
+		return req
+	}
+}
+
+// joinInts joins ints into a comma separated list, the way the API expects lists in query params
+func joinInts(ints []int) string {
+	strs := make([]string, len(ints))
+	for i, n := range ints {
+		strs[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// OptionSiteURL sends the request to the Bungie site at siteURL instead of the client's. The request is
+// still made to the same path under <siteURL>/Platform. siteURL must be a valid URL
+func OptionSiteURL(siteURL string) RequestOption {
+	return func(req *http.Request) *http.Request {
+		u, err := url.Parse(siteURL)
+		if err != nil {
+			return req
+		}
+
+		req.URL.Scheme = u.Scheme
+		req.URL.Host = u.Host
+		req.Host = u.Host
+		return req
+	}
+}
+
 // OptionOAuthToken sets the authorization header on the request to the provided token
 func OptionOAuthToken(t *oauth2.Token) RequestOption {
 	return func(req *http.Request) *http.Request {
@@ -59,7 +147,7 @@ func OptionOAuthToken(t *oauth2.Token) RequestOption {
 
 // APIResponse represents part of a response from returned from every Bungie API endpoint
 type APIResponse struct {
-	ErrorCode          int               `json:"ErrorCode"`
+	ErrorCode          PlatformErrorCode `json:"ErrorCode"`
 	ThrottleSeconds    int               `json:"ThrottleSeconds"`
 	ErrorStatus        string            `json:"ErrorStatus"`
 	Message            string            `json:"Message"`
@@ -75,30 +163,89 @@ type PagedQuery struct {
 	RequestContinuationToken string `json:"requestContinuationToken"`
 }
 
+// Int64Slice is a slice of int64s that the Bungie API encodes as an array of strings
+type Int64Slice []int64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *Int64Slice) UnmarshalJSON(b []byte) error {
+	var strs []json.Number
+	if err := json.Unmarshal(b, &strs); err != nil {
+		return err
+	}
+
+	*s = make(Int64Slice, len(strs))
+	for i, str := range strs {
+		n, err := str.Int64()
+		if err != nil {
+			return err
+		}
+		(*s)[i] = n
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the slice as an array of strings like the Bungie API
+func (s Int64Slice) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	strs := make([]string, len(s))
+	for i, n := range s {
+		strs[i] = strconv.FormatInt(n, 10)
+	}
+
+	return json.Marshal(strs)
+}
+
 // Client is used to communicate with the Desinty 2 API
 type Client struct {
 	httpClient   *http.Client
 	apiKey       string
+	siteURL      string
+	statsSiteURL string
 	oauth2Config *oauth2.Config
+	retryPolicy  RetryPolicy
+	limiter      *RateLimiter
+	middleware   []Middleware
+	cache        Cache
+	flights      *flightGroup
+	refreshes    *refreshGroup
+
+	// authenticated is true if the http client adds an OAuth token to every request
+	authenticated bool
 
 	GroupV2Service  *GroupV2Service
 	Destiny2Service *Destiny2Service
 	UserService     *UserService
+	ManifestService *ManifestService
 }
 
 // NewClient creates and returns a new client
 func NewClient(apiKey string) *Client {
 	c := &Client{
-		apiKey:     apiKey,
-		httpClient: http.DefaultClient,
+		apiKey:       apiKey,
+		siteURL:      SiteURL,
+		statsSiteURL: StatsSiteURL,
+		httpClient:   http.DefaultClient,
+		retryPolicy:  DefaultRetryPolicy,
+		limiter:      NewRateLimiter(DefaultRate, DefaultBurst),
+		flights:      newFlightGroup(),
+		refreshes:    newRefreshGroup(),
 	}
 
-	// Adding services to client
+	c.initServices()
+
+	return c
+}
+
+// initServices adds services to the client that point back to the client
+func (c *Client) initServices() {
 	c.GroupV2Service = &GroupV2Service{c}
 	c.Destiny2Service = &Destiny2Service{c}
 	c.UserService = &UserService{c}
-
-	return c
+	c.ManifestService = &ManifestService{c}
 }
 
 // SetOAuthCredentials sets the OAuth2 credentials on the client so the client can generate authroize URLs
@@ -107,15 +254,39 @@ func (c *Client) SetOAuthCredentials(clientID, clientSecret string) *Client {
 	c.oauth2Config = &oauth2.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  "https://www.bungie.net/en/oauth/authorize",
-			TokenURL: "https://www.bungie.net/Platform/App/OAuth/token/",
-		},
+		Endpoint:     c.oauthEndpoint(),
 	}
 
 	return c
 }
 
+// oauthEndpoint returns the OAuth2 endpoint of the site the client is communicating with
+func (c *Client) oauthEndpoint() oauth2.Endpoint {
+	return oauth2.Endpoint{
+		AuthURL:  c.siteURL + "/en/oauth/authorize",
+		TokenURL: c.siteURL + "/Platform/App/OAuth/token/",
+	}
+}
+
+// SetSiteURL sets the URL of the Bungie website the client communicates with. API calls are made to
+// paths under <siteURL>/Platform. Useful for pointing the client at a fake server when testing.
+// Function returns self for ease of chaining
+func (c *Client) SetSiteURL(siteURL string) *Client {
+	c.siteURL = strings.TrimSuffix(siteURL, "/")
+	if c.oauth2Config != nil {
+		c.oauth2Config.Endpoint = c.oauthEndpoint()
+	}
+
+	return c
+}
+
+// SetStatsSiteURL sets the URL of the Bungie site the client requests endpoints only served by the stats
+// host from. Function returns self for ease of chaining
+func (c *Client) SetStatsSiteURL(statsSiteURL string) *Client {
+	c.statsSiteURL = strings.TrimSuffix(statsSiteURL, "/")
+	return c
+}
+
 // GetOAuthConfig gets the OAuth2 config set on the client.
 // If no config has been set yet using Client.SetOAuthCredentials an zero config will be returned
 func (c *Client) GetOAuthConfig() oauth2.Config {
@@ -127,10 +298,12 @@ func (c *Client) GetOAuthConfig() oauth2.Config {
 }
 
 // WithOAuth2Token returns a copy of Client but with the http client being set to one that is authorized
-// to make calls to authenticated endpoints
-func (c Client) WithOAuth2Token(t *oauth2.Token) *Client {
-	ctx := context.Background()
-	c.SetHTTPClient(c.oauth2Config.Client(ctx, t))
+// to make calls to authenticated endpoints. ctx is used when the token needs to be refreshed so it should
+// live as long as the returned client is used
+func (c Client) WithOAuth2Token(ctx context.Context, t *oauth2.Token) *Client {
+	c.SetHTTPClient(c.oauth2Config.Client(c.oauthContext(ctx), t))
+	c.authenticated = true
+	c.initServices()
 
 	return &c
 }
@@ -142,6 +315,34 @@ func (c *Client) SetHTTPClient(httpClient *http.Client) *Client {
 	return c
 }
 
+// plainHTTPClient returns the client's HTTP client without the OAuth2 transport added by
+// Client.WithOAuth2Token so requests made with it carry no credentials
+func (c *Client) plainHTTPClient() *http.Client {
+	t, ok := c.httpClient.Transport.(*oauth2.Transport)
+	if !ok {
+		return c.httpClient
+	}
+
+	plain := *c.httpClient
+	plain.Transport = t.Base
+	return &plain
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests. The policy can be overridden for a single
+// request with OptionRetryPolicy. Function returns self for ease of chaining
+func (c *Client) SetRetryPolicy(p RetryPolicy) *Client {
+	c.retryPolicy = p
+	return c
+}
+
+// SetRateLimit limits the client, and every copy of it made with Client.WithOAuth2Token, to rate requests
+// per second with bursts of up to burst requests. A rate of 0 or less disables the limit. Function returns
+// self for ease of chaining
+func (c *Client) SetRateLimit(rate float64, burst int) *Client {
+	c.limiter.SetLimit(rate, burst)
+	return c
+}
+
 // GetAuthURL generates a auth URL to send to a user so they can authorize the app to access their account information.
 // State is not nessesary but is strongly advised
 func (c *Client) GetAuthURL(state string) string {
@@ -151,15 +352,28 @@ func (c *Client) GetAuthURL(state string) string {
 // Exchange exchanges the code provided with Destiny 2's token servers for an access token that can be used to create a client
 // for making authroized requests to endpoints that require authentication
 func (c *Client) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
-	return c.oauth2Config.Exchange(ctx, code)
+	return c.oauth2Config.Exchange(c.oauthContext(ctx), code)
 }
 
-func (c *Client) do(method, endpoint string, dst interface{}, opts ...RequestOption) error {
-	u, _ := url.Parse(BaseURL)
-	u.Path = path.Join(u.Path, endpoint) + "/"
+// oauthContext returns a context that makes the oauth2 package send requests with the client's http client,
+// unless ctx already specifies one
+func (c *Client) oauthContext(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, dst interface{}, opts ...RequestOption) error {
+	// Endpoints are escaped paths so anything a user provides must be escaped with url.PathEscape
+	u, err := url.Parse(c.siteURL + "/Platform" + path.Join("/", endpoint) + "/")
+	if err != nil {
+		return err
+	}
 
 	// Creating request
-	req, err := http.NewRequest(method, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
 	if err != nil {
 		return err
 	}
@@ -170,30 +384,91 @@ func (c *Client) do(method, endpoint string, dst interface{}, opts ...RequestOpt
 		req = opt(req)
 	}
 
-	// Sending request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doer().Do(req)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	if resp == nil {
+		return ErrNoResponse
+	}
+	if resp.StatusCode == http.StatusNotModified && resp.Body == nil {
+		return ErrNotModified
+	}
+
+	return json.Unmarshal(resp.Body, dst)
+}
+
+// exchange sends the request, waiting for the rate limiter and retrying it if it fails and the retry
+// policy allows it
+func (c *Client) exchange(req *http.Request) (*Response, error) {
+
+	// Making sure the body can be sent again if the request is going to be retried
+	policy := retryPolicyFromRequest(req, c.retryPolicy)
+	retry := policy.allows(req)
+	if retry {
+		if err := rewindableBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+
+		// Waiting for our turn so we do not go over Bungie's rate limits
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.send(req)
+		if err == nil || !retry || attempt >= policy.MaxAttempts || !shouldRetry(req.Context(), err) {
+			return resp, err
+		}
+
+		// Waiting before trying again, giving up if the request is cancelled while waiting
+		if err = sleep(req.Context(), policy.backoff(attempt, err)); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// send sends the request and decodes the envelope of the response
+func (c *Client) send(req *http.Request) (*Response, error) {
+
+	// Sending request
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	resp := &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+	}
+
+	// Response to a conditional request made by the cache, there is no body to decode
+	if resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
 
 	// Getting mime type to determine if we can json parse the body or if the request errored
 	// and we should check for error on body
-	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	contentType, _, err := mime.ParseMediaType(httpResp.Header.Get("Content-Type"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Request errored and bungie did not return JSON in the body so we have to rely on the status
 	// code to determine what went wrong
 	if contentType != "application/json" {
-		switch resp.StatusCode {
-		case http.StatusUnauthorized:
-			return ErrUnautorized
-		case http.StatusNotFound:
-			return ErrNotFound
-		default:
-			return ErrUnknown
+		return resp, &APIError{
+			StatusCode: resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
@@ -203,21 +478,20 @@ func (c *Client) do(method, endpoint string, dst interface{}, opts ...RequestOpt
 		Response json.RawMessage `json:"Response"`
 		APIResponse
 	}
-	if err = json.NewDecoder(resp.Body).Decode(&respStruct); err != nil {
-		return err
+	if err = json.NewDecoder(httpResp.Body).Decode(&respStruct); err != nil {
+		return nil, err
 	}
+	resp.APIResponse = respStruct.APIResponse
+	resp.Body = respStruct.Response
 
 	// Request errored
-	if respStruct.ErrorCode != 1 {
-		switch respStruct.ErrorCode {
-		case 21:
-			return ErrNotFound
-		case 99:
-			return ErrWebAuthRequired
-		default:
-			return ErrUnknown
+	if respStruct.ErrorCode != CodeSuccess {
+		return resp, &APIError{
+			APIResponse: respStruct.APIResponse,
+			StatusCode:  resp.StatusCode,
+			retryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
-	return json.Unmarshal(respStruct.Response, dst)
+	return resp, nil
 }
@@ -0,0 +1,896 @@
+package destiny2
+
+import "time"
+
+// SingleComponentResponseOfDestinyVendorReceiptsComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyVendorReceiptsComponent.html#schema_SingleComponentResponseOfDestinyVendorReceiptsComponent
+type SingleComponentResponseOfDestinyVendorReceiptsComponent struct {
+	Data    DestinyVendorReceiptsComponent `json:"data"`
+	Privacy int                            `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyInventoryComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyInventoryComponent.html#schema_SingleComponentResponseOfDestinyInventoryComponent
+type SingleComponentResponseOfDestinyInventoryComponent struct {
+	Data    DestinyInventoryComponent `json:"data"`
+	Privacy int                       `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyPlatformSilverComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyPlatformSilverComponent.html#schema_SingleComponentResponseOfDestinyPlatformSilverComponent
+type SingleComponentResponseOfDestinyPlatformSilverComponent struct {
+	Data    DestinyPlatformSilverComponent `json:"data"`
+	Privacy int                            `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyKiosksComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyKiosksComponent.html#schema_SingleComponentResponseOfDestinyKiosksComponent
+type SingleComponentResponseOfDestinyKiosksComponent struct {
+	Data    DestinyKiosksComponent `json:"data"`
+	Privacy int                    `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyPlugSetsComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyPlugSetsComponent.html#schema_SingleComponentResponseOfDestinyPlugSetsComponent
+type SingleComponentResponseOfDestinyPlugSetsComponent struct {
+	Data    DestinyPlugSetsComponent `json:"data"`
+	Privacy int                      `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyProfileProgressionComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyProfileProgressionComponent.html#schema_SingleComponentResponseOfDestinyProfileProgressionComponent
+type SingleComponentResponseOfDestinyProfileProgressionComponent struct {
+	Data    DestinyProfileProgressionComponent `json:"data"`
+	Privacy int                                `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyPresentationNodesComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyPresentationNodesComponent.html#schema_SingleComponentResponseOfDestinyPresentationNodesComponent
+type SingleComponentResponseOfDestinyPresentationNodesComponent struct {
+	Data    DestinyPresentationNodesComponent `json:"data"`
+	Privacy int                               `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyProfileRecordsComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyProfileRecordsComponent.html#schema_SingleComponentResponseOfDestinyProfileRecordsComponent
+type SingleComponentResponseOfDestinyProfileRecordsComponent struct {
+	Data    DestinyProfileRecordsComponent `json:"data"`
+	Privacy int                            `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyProfileCollectiblesComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyProfileCollectiblesComponent.html#schema_SingleComponentResponseOfDestinyProfileCollectiblesComponent
+type SingleComponentResponseOfDestinyProfileCollectiblesComponent struct {
+	Data    DestinyProfileCollectiblesComponent `json:"data"`
+	Privacy int                                 `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyProfileTransitoryComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyProfileTransitoryComponent.html#schema_SingleComponentResponseOfDestinyProfileTransitoryComponent
+type SingleComponentResponseOfDestinyProfileTransitoryComponent struct {
+	Data    DestinyProfileTransitoryComponent `json:"data"`
+	Privacy int                               `json:"privacy"`
+}
+
+// SingleComponentResponseOfDestinyMetricsComponent ...
+// https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyMetricsComponent.html#schema_SingleComponentResponseOfDestinyMetricsComponent
+type SingleComponentResponseOfDestinyMetricsComponent struct {
+	Data    DestinyMetricsComponent `json:"data"`
+	Privacy int                     `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyInventoryComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyInventoryComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyInventoryComponent
+type DictionaryComponentResponseOfint64AndDestinyInventoryComponent struct {
+	Data    map[int64]DestinyInventoryComponent `json:"data"`
+	Privacy int                                 `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyCharacterProgressionComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyCharacterProgressionComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyCharacterProgressionComponent
+type DictionaryComponentResponseOfint64AndDestinyCharacterProgressionComponent struct {
+	Data    map[int64]DestinyCharacterProgressionComponent `json:"data"`
+	Privacy int                                            `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyCharacterRenderComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyCharacterRenderComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyCharacterRenderComponent
+type DictionaryComponentResponseOfint64AndDestinyCharacterRenderComponent struct {
+	Data    map[int64]DestinyCharacterRenderComponent `json:"data"`
+	Privacy int                                       `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyCharacterActivitiesComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyCharacterActivitiesComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyCharacterActivitiesComponent
+type DictionaryComponentResponseOfint64AndDestinyCharacterActivitiesComponent struct {
+	Data    map[int64]DestinyCharacterActivitiesComponent `json:"data"`
+	Privacy int                                           `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyKiosksComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyKiosksComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyKiosksComponent
+type DictionaryComponentResponseOfint64AndDestinyKiosksComponent struct {
+	Data    map[int64]DestinyKiosksComponent `json:"data"`
+	Privacy int                              `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyPlugSetsComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyPlugSetsComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyPlugSetsComponent
+type DictionaryComponentResponseOfint64AndDestinyPlugSetsComponent struct {
+	Data    map[int64]DestinyPlugSetsComponent `json:"data"`
+	Privacy int                                `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyPresentationNodesComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyPresentationNodesComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyPresentationNodesComponent
+type DictionaryComponentResponseOfint64AndDestinyPresentationNodesComponent struct {
+	Data    map[int64]DestinyPresentationNodesComponent `json:"data"`
+	Privacy int                                         `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyCharacterRecordsComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyCharacterRecordsComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyCharacterRecordsComponent
+type DictionaryComponentResponseOfint64AndDestinyCharacterRecordsComponent struct {
+	Data    map[int64]DestinyCharacterRecordsComponent `json:"data"`
+	Privacy int                                        `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyCollectiblesComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyCollectiblesComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyCollectiblesComponent
+type DictionaryComponentResponseOfint64AndDestinyCollectiblesComponent struct {
+	Data    map[int64]DestinyCollectiblesComponent `json:"data"`
+	Privacy int                                    `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyCurrenciesComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyCurrenciesComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyCurrenciesComponent
+type DictionaryComponentResponseOfint64AndDestinyCurrenciesComponent struct {
+	Data    map[int64]DestinyCurrenciesComponent `json:"data"`
+	Privacy int                                  `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemInstanceComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemInstanceComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemInstanceComponent
+type DictionaryComponentResponseOfint64AndDestinyItemInstanceComponent struct {
+	Data    map[int64]DestinyItemInstanceComponent `json:"data"`
+	Privacy int                                    `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemObjectivesComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemObjectivesComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemObjectivesComponent
+type DictionaryComponentResponseOfint64AndDestinyItemObjectivesComponent struct {
+	Data    map[int64]DestinyItemObjectivesComponent `json:"data"`
+	Privacy int                                      `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemPerksComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemPerksComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemPerksComponent
+type DictionaryComponentResponseOfint64AndDestinyItemPerksComponent struct {
+	Data    map[int64]DestinyItemPerksComponent `json:"data"`
+	Privacy int                                 `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemRenderComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemRenderComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemRenderComponent
+type DictionaryComponentResponseOfint64AndDestinyItemRenderComponent struct {
+	Data    map[int64]DestinyItemRenderComponent `json:"data"`
+	Privacy int                                  `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemStatsComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemStatsComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemStatsComponent
+type DictionaryComponentResponseOfint64AndDestinyItemStatsComponent struct {
+	Data    map[int64]DestinyItemStatsComponent `json:"data"`
+	Privacy int                                 `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemSocketsComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemSocketsComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemSocketsComponent
+type DictionaryComponentResponseOfint64AndDestinyItemSocketsComponent struct {
+	Data    map[int64]DestinyItemSocketsComponent `json:"data"`
+	Privacy int                                   `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemReusablePlugsComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemReusablePlugsComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemReusablePlugsComponent
+type DictionaryComponentResponseOfint64AndDestinyItemReusablePlugsComponent struct {
+	Data    map[int64]DestinyItemReusablePlugsComponent `json:"data"`
+	Privacy int                                         `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemPlugObjectivesComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemPlugObjectivesComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemPlugObjectivesComponent
+type DictionaryComponentResponseOfint64AndDestinyItemPlugObjectivesComponent struct {
+	Data    map[int64]DestinyItemPlugObjectivesComponent `json:"data"`
+	Privacy int                                          `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfint64AndDestinyItemTalentGridComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyItemTalentGridComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyItemTalentGridComponent
+type DictionaryComponentResponseOfint64AndDestinyItemTalentGridComponent struct {
+	Data    map[int64]DestinyItemTalentGridComponent `json:"data"`
+	Privacy int                                      `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfuint32AndDestinyItemPlugComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfuint32AndDestinyItemPlugComponent.html#schema_DictionaryComponentResponseOfuint32AndDestinyItemPlugComponent
+type DictionaryComponentResponseOfuint32AndDestinyItemPlugComponent struct {
+	Data    map[uint]DestinyItemPlugComponent `json:"data"`
+	Privacy int                               `json:"privacy"`
+}
+
+// DictionaryComponentResponseOfuint32AndDestinyItemObjectivesComponent ...
+// https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfuint32AndDestinyItemObjectivesComponent.html#schema_DictionaryComponentResponseOfuint32AndDestinyItemObjectivesComponent
+type DictionaryComponentResponseOfuint32AndDestinyItemObjectivesComponent struct {
+	Data    map[uint]DestinyItemObjectivesComponent `json:"data"`
+	Privacy int                                     `json:"privacy"`
+}
+
+// DestinyItemComponentSetOfint64 ...
+// https://bungie-net.github.io/multi/schema_DestinyItemComponentSetOfint64.html#schema_DestinyItemComponentSetOfint64
+type DestinyItemComponentSetOfint64 struct {
+	Instances      *DictionaryComponentResponseOfint64AndDestinyItemInstanceComponent       `json:"instances"`
+	Objectives     *DictionaryComponentResponseOfint64AndDestinyItemObjectivesComponent     `json:"objectives"`
+	Perks          *DictionaryComponentResponseOfint64AndDestinyItemPerksComponent          `json:"perks"`
+	RenderData     *DictionaryComponentResponseOfint64AndDestinyItemRenderComponent         `json:"renderData"`
+	Stats          *DictionaryComponentResponseOfint64AndDestinyItemStatsComponent          `json:"stats"`
+	Sockets        *DictionaryComponentResponseOfint64AndDestinyItemSocketsComponent        `json:"sockets"`
+	ReusablePlugs  *DictionaryComponentResponseOfint64AndDestinyItemReusablePlugsComponent  `json:"reusablePlugs"`
+	PlugObjectives *DictionaryComponentResponseOfint64AndDestinyItemPlugObjectivesComponent `json:"plugObjectives"`
+	TalentGrids    *DictionaryComponentResponseOfint64AndDestinyItemTalentGridComponent     `json:"talentGrids"`
+	PlugStates     *DictionaryComponentResponseOfuint32AndDestinyItemPlugComponent          `json:"plugStates"`
+}
+
+// DestinyBaseItemComponentSetOfuint32 ...
+// https://bungie-net.github.io/multi/schema_DestinyBaseItemComponentSetOfuint32.html#schema_DestinyBaseItemComponentSetOfuint32
+type DestinyBaseItemComponentSetOfuint32 struct {
+	Objectives *DictionaryComponentResponseOfuint32AndDestinyItemObjectivesComponent `json:"objectives"`
+}
+
+// DestinyVendorReceiptsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Profiles-DestinyVendorReceiptsComponent.html#schema_Destiny-Entities-Profiles-DestinyVendorReceiptsComponent
+type DestinyVendorReceiptsComponent struct {
+	Receipts []DestinyVendorReceipt `json:"receipts"`
+}
+
+// DestinyVendorReceipt ...
+// https://bungie-net.github.io/multi/schema_Destiny-Vendors-DestinyVendorReceipt.html#schema_Destiny-Vendors-DestinyVendorReceipt
+type DestinyVendorReceipt struct {
+	CurrencyPaid           []DestinyItemQuantity `json:"currencyPaid"`
+	ItemReceived           DestinyItemQuantity   `json:"itemReceived"`
+	LicenseUnlockHash      uint                  `json:"licenseUnlockHash"`
+	PurchasedByCharacterID int64                 `json:"purchasedByCharacterId,string"`
+	RefundPolicy           int                   `json:"refundPolicy"`
+	SequenceNumber         int                   `json:"sequenceNumber"`
+	TimeToExpiration       int64                 `json:"timeToExpiration,string"`
+	ExpiresOn              time.Time             `json:"expiresOn"`
+}
+
+// DestinyItemQuantity ...
+// https://bungie-net.github.io/multi/schema_Destiny-DestinyItemQuantity.html#schema_Destiny-DestinyItemQuantity
+type DestinyItemQuantity struct {
+	ItemHash                 uint   `json:"itemHash"`
+	ItemInstanceID           *int64 `json:"itemInstanceId,string"`
+	Quantity                 int    `json:"quantity"`
+	HasConditionalVisibility bool   `json:"hasConditionalVisibility"`
+}
+
+// DestinyInventoryComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Inventory-DestinyInventoryComponent.html#schema_Destiny-Entities-Inventory-DestinyInventoryComponent
+type DestinyInventoryComponent struct {
+	Items []DestinyItemComponent `json:"items"`
+}
+
+// DestinyItemComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemComponent.html#schema_Destiny-Entities-Items-DestinyItemComponent
+type DestinyItemComponent struct {
+	ItemHash                   uint                      `json:"itemHash"`
+	ItemInstanceID             *int64                    `json:"itemInstanceId,string"`
+	Quantity                   int                       `json:"quantity"`
+	BindStatus                 int                       `json:"bindStatus"`
+	Location                   int                       `json:"location"`
+	BucketHash                 uint                      `json:"bucketHash"`
+	TransferStatus             int                       `json:"transferStatus"`
+	Lockable                   bool                      `json:"lockable"`
+	State                      int                       `json:"state"`
+	OverrideStyleItemHash      *uint                     `json:"overrideStyleItemHash"`
+	ExpirationDate             *time.Time                `json:"expirationDate"`
+	IsWrapper                  bool                      `json:"isWrapper"`
+	TooltipNotificationIndexes []int                     `json:"tooltipNotificationIndexes"`
+	MetricHash                 *uint                     `json:"metricHash"`
+	MetricObjective            *DestinyObjectiveProgress `json:"metricObjective"`
+	VersionNumber              *int                      `json:"versionNumber"`
+}
+
+// DestinyObjectiveProgress ...
+// https://bungie-net.github.io/multi/schema_Destiny-Quests-DestinyObjectiveProgress.html#schema_Destiny-Quests-DestinyObjectiveProgress
+type DestinyObjectiveProgress struct {
+	ObjectiveHash   uint  `json:"objectiveHash"`
+	DestinationHash *uint `json:"destinationHash"`
+	ActivityHash    *uint `json:"activityHash"`
+	Progress        *int  `json:"progress"`
+	CompletionValue int   `json:"completionValue"`
+	Complete        bool  `json:"complete"`
+	Visible         bool  `json:"visible"`
+}
+
+// DestinyPlatformSilverComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Inventory-DestinyPlatformSilverComponent.html#schema_Destiny-Components-Inventory-DestinyPlatformSilverComponent
+type DestinyPlatformSilverComponent struct {
+	PlatformSilver map[int]DestinyItemComponent `json:"platformSilver"`
+}
+
+// DestinyKiosksComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Kiosks-DestinyKiosksComponent.html#schema_Destiny-Components-Kiosks-DestinyKiosksComponent
+type DestinyKiosksComponent struct {
+	KioskItems map[uint][]DestinyKioskItem `json:"kioskItems"`
+}
+
+// DestinyKioskItem ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Kiosks-DestinyKioskItem.html#schema_Destiny-Components-Kiosks-DestinyKioskItem
+type DestinyKioskItem struct {
+	Index           int                       `json:"index"`
+	CanAcquire      bool                      `json:"canAcquire"`
+	FailureIndexes  []int                     `json:"failureIndexes"`
+	FlavorObjective *DestinyObjectiveProgress `json:"flavorObjective"`
+}
+
+// DestinyPlugSetsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-PlugSets-DestinyPlugSetsComponent.html#schema_Destiny-Components-PlugSets-DestinyPlugSetsComponent
+type DestinyPlugSetsComponent struct {
+	Plugs map[uint][]DestinyItemPlug `json:"plugs"`
+}
+
+// DestinyItemPlug ...
+// https://bungie-net.github.io/multi/schema_Destiny-Sockets-DestinyItemPlug.html#schema_Destiny-Sockets-DestinyItemPlug
+type DestinyItemPlug struct {
+	PlugObjectives    []DestinyObjectiveProgress `json:"plugObjectives"`
+	PlugItemHash      uint                       `json:"plugItemHash"`
+	CanInsert         bool                       `json:"canInsert"`
+	Enabled           bool                       `json:"enabled"`
+	InsertFailIndexes []int                      `json:"insertFailIndexes"`
+	EnableFailIndexes []int                      `json:"enableFailIndexes"`
+}
+
+// DestinyItemPlugComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Items-DestinyItemPlugComponent.html#schema_Destiny-Components-Items-DestinyItemPlugComponent
+type DestinyItemPlugComponent DestinyItemPlug
+
+// DestinyProfileProgressionComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Profiles-DestinyProfileProgressionComponent.html#schema_Destiny-Components-Profiles-DestinyProfileProgressionComponent
+type DestinyProfileProgressionComponent struct {
+	Checklists       map[uint]map[uint]bool       `json:"checklists"`
+	SeasonalArtifact DestinyArtifactProfileScoped `json:"seasonalArtifact"`
+}
+
+// DestinyArtifactProfileScoped ...
+// https://bungie-net.github.io/multi/schema_Destiny-Artifacts-DestinyArtifactProfileScoped.html#schema_Destiny-Artifacts-DestinyArtifactProfileScoped
+type DestinyArtifactProfileScoped struct {
+	ArtifactHash          uint               `json:"artifactHash"`
+	PointProgression      DestinyProgression `json:"pointProgression"`
+	PointsAcquired        int                `json:"pointsAcquired"`
+	PowerBonusProgression DestinyProgression `json:"powerBonusProgression"`
+	PowerBonus            int                `json:"powerBonus"`
+}
+
+// DestinyArtifactCharacterScoped ...
+// https://bungie-net.github.io/multi/schema_Destiny-Artifacts-DestinyArtifactCharacterScoped.html#schema_Destiny-Artifacts-DestinyArtifactCharacterScoped
+type DestinyArtifactCharacterScoped struct {
+	ArtifactHash uint                  `json:"artifactHash"`
+	PointsUsed   int                   `json:"pointsUsed"`
+	ResetCount   int                   `json:"resetCount"`
+	Tiers        []DestinyArtifactTier `json:"tiers"`
+}
+
+// DestinyArtifactTier ...
+// https://bungie-net.github.io/multi/schema_Destiny-Artifacts-DestinyArtifactTier.html#schema_Destiny-Artifacts-DestinyArtifactTier
+type DestinyArtifactTier struct {
+	TierHash       uint                      `json:"tierHash"`
+	IsUnlocked     bool                      `json:"isUnlocked"`
+	PointsToUnlock int                       `json:"pointsToUnlock"`
+	Items          []DestinyArtifactTierItem `json:"items"`
+}
+
+// DestinyArtifactTierItem ...
+// https://bungie-net.github.io/multi/schema_Destiny-Artifacts-DestinyArtifactTierItem.html#schema_Destiny-Artifacts-DestinyArtifactTierItem
+type DestinyArtifactTierItem struct {
+	ItemHash uint `json:"itemHash"`
+	IsActive bool `json:"isActive"`
+}
+
+// DestinyPresentationNodesComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Presentation-DestinyPresentationNodesComponent.html#schema_Destiny-Components-Presentation-DestinyPresentationNodesComponent
+type DestinyPresentationNodesComponent struct {
+	Nodes map[uint]DestinyPresentationNodeComponent `json:"nodes"`
+}
+
+// DestinyPresentationNodeComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Presentation-DestinyPresentationNodeComponent.html#schema_Destiny-Components-Presentation-DestinyPresentationNodeComponent
+type DestinyPresentationNodeComponent struct {
+	State               int                       `json:"state"`
+	Objective           *DestinyObjectiveProgress `json:"objective"`
+	ProgressValue       int                       `json:"progressValue"`
+	CompletionValue     int                       `json:"completionValue"`
+	RecordCategoryScore *int                      `json:"recordCategoryScore"`
+}
+
+// DestinyProfileRecordsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Records-DestinyProfileRecordsComponent.html#schema_Destiny-Components-Records-DestinyProfileRecordsComponent
+type DestinyProfileRecordsComponent struct {
+	Score                        int                             `json:"score"`
+	ActiveScore                  int                             `json:"activeScore"`
+	LegacyScore                  int                             `json:"legacyScore"`
+	LifetimeScore                int                             `json:"lifetimeScore"`
+	TrackedRecordHash            *uint                           `json:"trackedRecordHash"`
+	Records                      map[uint]DestinyRecordComponent `json:"records"`
+	RecordCategoriesRootNodeHash uint                            `json:"recordCategoriesRootNodeHash"`
+	RecordSealsRootNodeHash      uint                            `json:"recordSealsRootNodeHash"`
+}
+
+// DestinyCharacterRecordsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Records-DestinyCharacterRecordsComponent.html#schema_Destiny-Components-Records-DestinyCharacterRecordsComponent
+type DestinyCharacterRecordsComponent struct {
+	FeaturedRecordHashes         []uint                          `json:"featuredRecordHashes"`
+	Records                      map[uint]DestinyRecordComponent `json:"records"`
+	RecordCategoriesRootNodeHash uint                            `json:"recordCategoriesRootNodeHash"`
+	RecordSealsRootNodeHash      uint                            `json:"recordSealsRootNodeHash"`
+}
+
+// DestinyRecordComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Records-DestinyRecordComponent.html#schema_Destiny-Components-Records-DestinyRecordComponent
+type DestinyRecordComponent struct {
+	State                  int                        `json:"state"`
+	Objectives             []DestinyObjectiveProgress `json:"objectives"`
+	IntervalObjectives     []DestinyObjectiveProgress `json:"intervalObjectives"`
+	IntervalsRedeemedCount int                        `json:"intervalsRedeemedCount"`
+	CompletedCount         *int                       `json:"completedCount"`
+	RewardVisibility       []bool                     `json:"rewardVisibilty"`
+}
+
+// DestinyProfileCollectiblesComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Collectibles-DestinyProfileCollectiblesComponent.html#schema_Destiny-Components-Collectibles-DestinyProfileCollectiblesComponent
+type DestinyProfileCollectiblesComponent struct {
+	RecentCollectibleHashes          []uint                               `json:"recentCollectibleHashes"`
+	NewnessFlaggedCollectibleHashes  []uint                               `json:"newnessFlaggedCollectibleHashes"`
+	Collectibles                     map[uint]DestinyCollectibleComponent `json:"collectibles"`
+	CollectionCategoriesRootNodeHash uint                                 `json:"collectionCategoriesRootNodeHash"`
+	CollectionBadgesRootNodeHash     uint                                 `json:"collectionBadgesRootNodeHash"`
+}
+
+// DestinyCollectiblesComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Collectibles-DestinyCollectiblesComponent.html#schema_Destiny-Components-Collectibles-DestinyCollectiblesComponent
+type DestinyCollectiblesComponent struct {
+	Collectibles                     map[uint]DestinyCollectibleComponent `json:"collectibles"`
+	CollectionCategoriesRootNodeHash uint                                 `json:"collectionCategoriesRootNodeHash"`
+	CollectionBadgesRootNodeHash     uint                                 `json:"collectionBadgesRootNodeHash"`
+}
+
+// DestinyCollectibleComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Collectibles-DestinyCollectibleComponent.html#schema_Destiny-Components-Collectibles-DestinyCollectibleComponent
+type DestinyCollectibleComponent struct {
+	State int `json:"state"`
+}
+
+// DestinyProfileTransitoryComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Profiles-DestinyProfileTransitoryComponent.html#schema_Destiny-Components-Profiles-DestinyProfileTransitoryComponent
+type DestinyProfileTransitoryComponent struct {
+	PartyMembers               []DestinyProfileTransitoryPartyMember    `json:"partyMembers"`
+	CurrentActivity            *DestinyProfileTransitoryCurrentActivity `json:"currentActivity"`
+	Joinability                DestinyProfileTransitoryJoinability      `json:"joinability"`
+	Tracking                   []DestinyProfileTransitoryTrackingEntry  `json:"tracking"`
+	LastOrbitedDestinationHash *uint                                    `json:"lastOrbitedDestinationHash"`
+}
+
+// DestinyProfileTransitoryPartyMember ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Profiles-DestinyProfileTransitoryPartyMember.html#schema_Destiny-Components-Profiles-DestinyProfileTransitoryPartyMember
+type DestinyProfileTransitoryPartyMember struct {
+	MembershipID int64  `json:"membershipId,string"`
+	EmblemHash   uint   `json:"emblemHash"`
+	DisplayName  string `json:"displayName"`
+	Status       int    `json:"status"`
+}
+
+// DestinyProfileTransitoryCurrentActivity ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Profiles-DestinyProfileTransitoryCurrentActivity.html#schema_Destiny-Components-Profiles-DestinyProfileTransitoryCurrentActivity
+type DestinyProfileTransitoryCurrentActivity struct {
+	StartTime                   *time.Time `json:"startTime"`
+	EndTime                     *time.Time `json:"endTime"`
+	Score                       float32    `json:"score"`
+	HighestOpposingFactionScore float32    `json:"highestOpposingFactionScore"`
+	NumberOfOpponents           int        `json:"numberOfOpponents"`
+	NumberOfPlayers             int        `json:"numberOfPlayers"`
+}
+
+// DestinyProfileTransitoryJoinability ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Profiles-DestinyProfileTransitoryJoinability.html#schema_Destiny-Components-Profiles-DestinyProfileTransitoryJoinability
+type DestinyProfileTransitoryJoinability struct {
+	OpenSlots      int `json:"openSlots"`
+	PrivacySetting int `json:"privacySetting"`
+	ClosedReasons  int `json:"closedReasons"`
+}
+
+// DestinyProfileTransitoryTrackingEntry ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Profiles-DestinyProfileTransitoryTrackingEntry.html#schema_Destiny-Components-Profiles-DestinyProfileTransitoryTrackingEntry
+type DestinyProfileTransitoryTrackingEntry struct {
+	LocationHash      *uint      `json:"locationHash"`
+	ItemHash          *uint      `json:"itemHash"`
+	ObjectiveHash     *uint      `json:"objectiveHash"`
+	ActivityHash      *uint      `json:"activityHash"`
+	QuestlineItemHash *uint      `json:"questlineItemHash"`
+	TrackedDate       *time.Time `json:"trackedDate"`
+}
+
+// DestinyMetricsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Metrics-DestinyMetricsComponent.html#schema_Destiny-Components-Metrics-DestinyMetricsComponent
+type DestinyMetricsComponent struct {
+	Metrics             map[uint]DestinyMetricComponent `json:"metrics"`
+	MetricsRootNodeHash uint                            `json:"metricsRootNodeHash"`
+}
+
+// DestinyMetricComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Metrics-DestinyMetricComponent.html#schema_Destiny-Components-Metrics-DestinyMetricComponent
+type DestinyMetricComponent struct {
+	Invisible         bool                     `json:"invisible"`
+	ObjectiveProgress DestinyObjectiveProgress `json:"objectiveProgress"`
+}
+
+// DestinyCharacterProgressionComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Characters-DestinyCharacterProgressionComponent.html#schema_Destiny-Entities-Characters-DestinyCharacterProgressionComponent
+type DestinyCharacterProgressionComponent struct {
+	Progressions              map[uint]DestinyProgression         `json:"progressions"`
+	Factions                  map[uint]DestinyFactionProgression  `json:"factions"`
+	Milestones                map[uint]DestinyMilestone           `json:"milestones"`
+	Quests                    []DestinyQuestStatus                `json:"quests"`
+	UninstancedItemObjectives map[uint][]DestinyObjectiveProgress `json:"uninstancedItemObjectives"`
+	Checklists                map[uint]map[uint]bool              `json:"checklists"`
+	SeasonalArtifact          DestinyArtifactCharacterScoped      `json:"seasonalArtifact"`
+}
+
+// DestinyFactionProgression ...
+// https://bungie-net.github.io/multi/schema_Destiny-Progression-DestinyFactionProgression.html#schema_Destiny-Progression-DestinyFactionProgression
+type DestinyFactionProgression struct {
+	DestinyProgression
+	FactionHash        uint `json:"factionHash"`
+	FactionVendorIndex int  `json:"factionVendorIndex"`
+}
+
+// DestinyMilestone ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestone.html#schema_Destiny-Milestones-DestinyMilestone
+type DestinyMilestone struct {
+	MilestoneHash   uint                                `json:"milestoneHash"`
+	AvailableQuests []DestinyMilestoneQuest             `json:"availableQuests"`
+	Activities      []DestinyMilestoneChallengeActivity `json:"activities"`
+	Values          map[string]float32                  `json:"values"`
+	VendorHashes    []uint                              `json:"vendorHashes"`
+	Vendors         []DestinyMilestoneVendor            `json:"vendors"`
+	Rewards         []DestinyMilestoneRewardCategory    `json:"rewards"`
+	StartDate       *time.Time                          `json:"startDate"`
+	EndDate         *time.Time                          `json:"endDate"`
+	Order           int                                 `json:"order"`
+}
+
+// DestinyMilestoneQuest ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneQuest.html#schema_Destiny-Milestones-DestinyMilestoneQuest
+type DestinyMilestoneQuest struct {
+	QuestItemHash uint                      `json:"questItemHash"`
+	Status        DestinyQuestStatus        `json:"status"`
+	Activity      *DestinyMilestoneActivity `json:"activity"`
+	Challenges    []DestinyChallengeStatus  `json:"challenges"`
+}
+
+// DestinyMilestoneActivity ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneActivity.html#schema_Destiny-Milestones-DestinyMilestoneActivity
+type DestinyMilestoneActivity struct {
+	ActivityHash     uint                              `json:"activityHash"`
+	ActivityModeHash *uint                             `json:"activityModeHash"`
+	ActivityModeType *DestinyActivityModeType          `json:"activityModeType"`
+	ModifierHashes   []uint                            `json:"modifierHashes"`
+	Variants         []DestinyMilestoneActivityVariant `json:"variants"`
+}
+
+// DestinyMilestoneActivityVariant ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneActivityVariant.html#schema_Destiny-Milestones-DestinyMilestoneActivityVariant
+type DestinyMilestoneActivityVariant struct {
+	ActivityHash     uint                                      `json:"activityHash"`
+	CompletionStatus *DestinyMilestoneActivityCompletionStatus `json:"completionStatus"`
+	ActivityModeHash *uint                                     `json:"activityModeHash"`
+	ActivityModeType *DestinyActivityModeType                  `json:"activityModeType"`
+}
+
+// DestinyMilestoneActivityCompletionStatus ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneActivityCompletionStatus.html#schema_Destiny-Milestones-DestinyMilestoneActivityCompletionStatus
+type DestinyMilestoneActivityCompletionStatus struct {
+	Completed bool                            `json:"completed"`
+	Phases    []DestinyMilestoneActivityPhase `json:"phases"`
+}
+
+// DestinyMilestoneActivityPhase ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneActivityPhase.html#schema_Destiny-Milestones-DestinyMilestoneActivityPhase
+type DestinyMilestoneActivityPhase struct {
+	Complete  bool `json:"complete"`
+	PhaseHash uint `json:"phaseHash"`
+}
+
+// DestinyMilestoneChallengeActivity ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneChallengeActivity.html#schema_Destiny-Milestones-DestinyMilestoneChallengeActivity
+type DestinyMilestoneChallengeActivity struct {
+	ActivityHash            uint                            `json:"activityHash"`
+	Challenges              []DestinyChallengeStatus        `json:"challenges"`
+	ModifierHashes          []uint                          `json:"modifierHashes"`
+	BooleanActivityOptions  map[uint]bool                   `json:"booleanActivityOptions"`
+	LoadoutRequirementIndex *int                            `json:"loadoutRequirementIndex"`
+	Phases                  []DestinyMilestoneActivityPhase `json:"phases"`
+}
+
+// DestinyMilestoneVendor ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneVendor.html#schema_Destiny-Milestones-DestinyMilestoneVendor
+type DestinyMilestoneVendor struct {
+	VendorHash      uint  `json:"vendorHash"`
+	PreviewItemHash *uint `json:"previewItemHash"`
+}
+
+// DestinyMilestoneRewardCategory ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneRewardCategory.html#schema_Destiny-Milestones-DestinyMilestoneRewardCategory
+type DestinyMilestoneRewardCategory struct {
+	RewardCategoryHash uint                          `json:"rewardCategoryHash"`
+	Entries            []DestinyMilestoneRewardEntry `json:"entries"`
+}
+
+// DestinyMilestoneRewardEntry ...
+// https://bungie-net.github.io/multi/schema_Destiny-Milestones-DestinyMilestoneRewardEntry.html#schema_Destiny-Milestones-DestinyMilestoneRewardEntry
+type DestinyMilestoneRewardEntry struct {
+	RewardEntryHash uint `json:"rewardEntryHash"`
+	Earned          bool `json:"earned"`
+	Redeemed        bool `json:"redeemed"`
+}
+
+// DestinyQuestStatus ...
+// https://bungie-net.github.io/multi/schema_Destiny-Quests-DestinyQuestStatus.html#schema_Destiny-Quests-DestinyQuestStatus
+type DestinyQuestStatus struct {
+	QuestHash      uint                       `json:"questHash"`
+	StepHash       uint                       `json:"stepHash"`
+	StepObjectives []DestinyObjectiveProgress `json:"stepObjectives"`
+	Tracked        bool                       `json:"tracked"`
+	ItemInstanceID int64                      `json:"itemInstanceId,string"`
+	Completed      bool                       `json:"completed"`
+	Redeemed       bool                       `json:"redeemed"`
+	Started        bool                       `json:"started"`
+	VendorHash     *uint                      `json:"vendorHash"`
+}
+
+// DestinyChallengeStatus ...
+// https://bungie-net.github.io/multi/schema_Destiny-Challenges-DestinyChallengeStatus.html#schema_Destiny-Challenges-DestinyChallengeStatus
+type DestinyChallengeStatus struct {
+	Objective DestinyObjectiveProgress `json:"objective"`
+}
+
+// DestinyCharacterRenderComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Characters-DestinyCharacterRenderComponent.html#schema_Destiny-Entities-Characters-DestinyCharacterRenderComponent
+type DestinyCharacterRenderComponent struct {
+	CustomDyes    []DyeReference                `json:"customDyes"`
+	Customization DestinyCharacterCustomization `json:"customization"`
+	PeerView      DestinyCharacterPeerView      `json:"peerView"`
+}
+
+// DyeReference ...
+// https://bungie-net.github.io/multi/schema_Destiny-DyeReference.html#schema_Destiny-DyeReference
+type DyeReference struct {
+	ChannelHash uint `json:"channelHash"`
+	DyeHash     uint `json:"dyeHash"`
+}
+
+// DestinyCharacterCustomization ...
+// https://bungie-net.github.io/multi/schema_Destiny-Character-DestinyCharacterCustomization.html#schema_Destiny-Character-DestinyCharacterCustomization
+type DestinyCharacterCustomization struct {
+	Personality   uint   `json:"personality"`
+	Face          uint   `json:"face"`
+	SkinColor     uint   `json:"skinColor"`
+	LipColor      uint   `json:"lipColor"`
+	EyeColor      uint   `json:"eyeColor"`
+	HairColors    []uint `json:"hairColors"`
+	FeatureColors []uint `json:"featureColors"`
+	DecalColor    uint   `json:"decalColor"`
+	WearHelmet    bool   `json:"wearHelmet"`
+	HairIndex     int    `json:"hairIndex"`
+	FeatureIndex  int    `json:"featureIndex"`
+	DecalIndex    int    `json:"decalIndex"`
+}
+
+// DestinyCharacterPeerView ...
+// https://bungie-net.github.io/multi/schema_Destiny-Character-DestinyCharacterPeerView.html#schema_Destiny-Character-DestinyCharacterPeerView
+type DestinyCharacterPeerView struct {
+	Equipment []DestinyItemPeerView `json:"equipment"`
+}
+
+// DestinyItemPeerView ...
+// https://bungie-net.github.io/multi/schema_Destiny-Character-DestinyItemPeerView.html#schema_Destiny-Character-DestinyItemPeerView
+type DestinyItemPeerView struct {
+	ItemHash uint           `json:"itemHash"`
+	Dyes     []DyeReference `json:"dyes"`
+}
+
+// DestinyCharacterActivitiesComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Characters-DestinyCharacterActivitiesComponent.html#schema_Destiny-Entities-Characters-DestinyCharacterActivitiesComponent
+type DestinyCharacterActivitiesComponent struct {
+	DateActivityStarted         time.Time                 `json:"dateActivityStarted"`
+	AvailableActivities         []DestinyActivity         `json:"availableActivities"`
+	CurrentActivityHash         uint                      `json:"currentActivityHash"`
+	CurrentActivityModeHash     uint                      `json:"currentActivityModeHash"`
+	CurrentActivityModeType     *DestinyActivityModeType  `json:"currentActivityModeType"`
+	CurrentActivityModeHashes   []uint                    `json:"currentActivityModeHashes"`
+	CurrentActivityModeTypes    []DestinyActivityModeType `json:"currentActivityModeTypes"`
+	CurrentPlaylistActivityHash *uint                     `json:"currentPlaylistActivityHash"`
+	LastCompletedStoryHash      uint                      `json:"lastCompletedStoryHash"`
+}
+
+// DestinyActivity ...
+// https://bungie-net.github.io/multi/schema_Destiny-DestinyActivity.html#schema_Destiny-DestinyActivity
+type DestinyActivity struct {
+	ActivityHash            uint                     `json:"activityHash"`
+	IsNew                   bool                     `json:"isNew"`
+	CanLead                 bool                     `json:"canLead"`
+	CanJoin                 bool                     `json:"canJoin"`
+	IsCompleted             bool                     `json:"isCompleted"`
+	IsVisible               bool                     `json:"isVisible"`
+	DisplayLevel            *int                     `json:"displayLevel"`
+	RecommendedLight        *int                     `json:"recommendedLight"`
+	DifficultyTier          int                      `json:"difficultyTier"`
+	Challenges              []DestinyChallengeStatus `json:"challenges"`
+	ModifierHashes          []uint                   `json:"modifierHashes"`
+	BooleanActivityOptions  map[uint]bool            `json:"booleanActivityOptions"`
+	LoadoutRequirementIndex *int                     `json:"loadoutRequirementIndex"`
+}
+
+// DestinyCurrenciesComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Inventory-DestinyCurrenciesComponent.html#schema_Destiny-Components-Inventory-DestinyCurrenciesComponent
+type DestinyCurrenciesComponent struct {
+	ItemQuantities map[uint]int `json:"itemQuantities"`
+}
+
+// DestinyItemInstanceComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemInstanceComponent.html#schema_Destiny-Entities-Items-DestinyItemInstanceComponent
+type DestinyItemInstanceComponent struct {
+	DamageType                  int                        `json:"damageType"`
+	DamageTypeHash              *uint                      `json:"damageTypeHash"`
+	PrimaryStat                 *DestinyStat               `json:"primaryStat"`
+	ItemLevel                   int                        `json:"itemLevel"`
+	Quality                     int                        `json:"quality"`
+	IsEquipped                  bool                       `json:"isEquipped"`
+	CanEquip                    bool                       `json:"canEquip"`
+	EquipRequiredLevel          int                        `json:"equipRequiredLevel"`
+	UnlockHashesRequiredToEquip []uint                     `json:"unlockHashesRequiredToEquip"`
+	CannotEquipReason           int                        `json:"cannotEquipReason"`
+	BreakerType                 *int                       `json:"breakerType"`
+	BreakerTypeHash             *uint                      `json:"breakerTypeHash"`
+	Energy                      *DestinyItemInstanceEnergy `json:"energy"`
+}
+
+// DestinyStat ...
+// https://bungie-net.github.io/multi/schema_Destiny-DestinyStat.html#schema_Destiny-DestinyStat
+type DestinyStat struct {
+	StatHash uint `json:"statHash"`
+	Value    int  `json:"value"`
+}
+
+// DestinyItemInstanceEnergy ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemInstanceEnergy.html#schema_Destiny-Entities-Items-DestinyItemInstanceEnergy
+type DestinyItemInstanceEnergy struct {
+	EnergyTypeHash uint `json:"energyTypeHash"`
+	EnergyType     int  `json:"energyType"`
+	EnergyCapacity int  `json:"energyCapacity"`
+	EnergyUsed     int  `json:"energyUsed"`
+	EnergyUnused   int  `json:"energyUnused"`
+}
+
+// DestinyItemObjectivesComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemObjectivesComponent.html#schema_Destiny-Entities-Items-DestinyItemObjectivesComponent
+type DestinyItemObjectivesComponent struct {
+	Objectives      []DestinyObjectiveProgress `json:"objectives"`
+	FlavorObjective *DestinyObjectiveProgress  `json:"flavorObjective"`
+	DateCompleted   *time.Time                 `json:"dateCompleted"`
+}
+
+// DestinyItemPerksComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemPerksComponent.html#schema_Destiny-Entities-Items-DestinyItemPerksComponent
+type DestinyItemPerksComponent struct {
+	Perks []DestinyPerkReference `json:"perks"`
+}
+
+// DestinyPerkReference ...
+// https://bungie-net.github.io/multi/schema_Destiny-Perks-DestinyPerkReference.html#schema_Destiny-Perks-DestinyPerkReference
+type DestinyPerkReference struct {
+	PerkHash uint   `json:"perkHash"`
+	IconPath string `json:"iconPath"`
+	IsActive bool   `json:"isActive"`
+	Visible  bool   `json:"visible"`
+}
+
+// DestinyItemRenderComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemRenderComponent.html#schema_Destiny-Entities-Items-DestinyItemRenderComponent
+type DestinyItemRenderComponent struct {
+	UseCustomDyes bool        `json:"useCustomDyes"`
+	ArtRegions    map[int]int `json:"artRegions"`
+}
+
+// DestinyItemStatsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemStatsComponent.html#schema_Destiny-Entities-Items-DestinyItemStatsComponent
+type DestinyItemStatsComponent struct {
+	Stats map[uint]DestinyStat `json:"stats"`
+}
+
+// DestinyItemSocketsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemSocketsComponent.html#schema_Destiny-Entities-Items-DestinyItemSocketsComponent
+type DestinyItemSocketsComponent struct {
+	Sockets []DestinyItemSocketState `json:"sockets"`
+}
+
+// DestinyItemSocketState ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemSocketState.html#schema_Destiny-Entities-Items-DestinyItemSocketState
+type DestinyItemSocketState struct {
+	PlugHash          *uint `json:"plugHash"`
+	IsEnabled         bool  `json:"isEnabled"`
+	IsVisible         bool  `json:"isVisible"`
+	EnableFailIndexes []int `json:"enableFailIndexes"`
+}
+
+// DestinyItemReusablePlugsComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Items-DestinyItemReusablePlugsComponent.html#schema_Destiny-Components-Items-DestinyItemReusablePlugsComponent
+type DestinyItemReusablePlugsComponent struct {
+	Plugs map[int][]DestinyItemPlugBase `json:"plugs"`
+}
+
+// DestinyItemPlugBase ...
+// https://bungie-net.github.io/multi/schema_Destiny-Sockets-DestinyItemPlugBase.html#schema_Destiny-Sockets-DestinyItemPlugBase
+type DestinyItemPlugBase struct {
+	PlugItemHash      uint  `json:"plugItemHash"`
+	CanInsert         bool  `json:"canInsert"`
+	Enabled           bool  `json:"enabled"`
+	InsertFailIndexes []int `json:"insertFailIndexes"`
+	EnableFailIndexes []int `json:"enableFailIndexes"`
+}
+
+// DestinyItemPlugObjectivesComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Components-Items-DestinyItemPlugObjectivesComponent.html#schema_Destiny-Components-Items-DestinyItemPlugObjectivesComponent
+type DestinyItemPlugObjectivesComponent struct {
+	ObjectivesPerPlug map[uint][]DestinyObjectiveProgress `json:"objectivesPerPlug"`
+}
+
+// DestinyItemTalentGridComponent ...
+// https://bungie-net.github.io/multi/schema_Destiny-Entities-Items-DestinyItemTalentGridComponent.html#schema_Destiny-Entities-Items-DestinyItemTalentGridComponent
+type DestinyItemTalentGridComponent struct {
+	TalentGridHash  uint                `json:"talentGridHash"`
+	Nodes           []DestinyTalentNode `json:"nodes"`
+	IsGridComplete  bool                `json:"isGridComplete"`
+	GridProgression *DestinyProgression `json:"gridProgression"`
+}
+
+// DestinyTalentNode ...
+// https://bungie-net.github.io/multi/schema_Destiny-DestinyTalentNode.html#schema_Destiny-DestinyTalentNode
+type DestinyTalentNode struct {
+	NodeIndex           int                          `json:"nodeIndex"`
+	NodeHash            uint                         `json:"nodeHash"`
+	State               int                          `json:"state"`
+	IsActivated         bool                         `json:"isActivated"`
+	StepIndex           int                          `json:"stepIndex"`
+	MaterialsToUpgrade  []DestinyMaterialRequirement `json:"materialsToUpgrade"`
+	ActivationGridLevel int                          `json:"activationGridLevel"`
+	ProgressPercent     float32                      `json:"progressPercent"`
+	Hidden              bool                         `json:"hidden"`
+	NodeStatsBlock      *DestinyTalentNodeStatBlock  `json:"nodeStatsBlock"`
+}
+
+// DestinyMaterialRequirement ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyMaterialRequirement.html#schema_Destiny-Definitions-DestinyMaterialRequirement
+type DestinyMaterialRequirement struct {
+	ItemHash             uint `json:"itemHash"`
+	DeleteOnAction       bool `json:"deleteOnAction"`
+	Count                int  `json:"count"`
+	OmitFromRequirements bool `json:"omitFromRequirements"`
+}
+
+// DestinyTalentNodeStatBlock ...
+// https://bungie-net.github.io/multi/schema_Destiny-DestinyTalentNodeStatBlock.html#schema_Destiny-DestinyTalentNodeStatBlock
+type DestinyTalentNodeStatBlock struct {
+	CurrentStepStats []DestinyStat `json:"currentStepStats"`
+	NextStepStats    []DestinyStat `json:"nextStepStats"`
+}
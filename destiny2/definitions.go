@@ -0,0 +1,285 @@
+package destiny2
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+
+	// DefaultDefinitionCacheSize is the number of definitions a Manifest keeps in memory
+	DefaultDefinitionCacheSize = 1024
+)
+
+// definitionKey is the key definitions are cached under
+type definitionKey struct {
+	table string
+	hash  uint32
+}
+
+// hashToID converts a definition hash to the signed ID used as the primary key of the manifest tables
+func hashToID(hash uint) int64 {
+	return int64(int32(uint32(hash)))
+}
+
+// getDefinition looks up the definition with the provided hash in table and decodes it into dst, which
+// must be a pointer. Decoded definitions are cached so their slices and maps are shared between callers
+// and must not be modified.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) getDefinition(table string, hash uint, dst interface{}) error {
+	key := definitionKey{table, uint32(hash)}
+	v := reflect.ValueOf(dst).Elem()
+
+	if def, ok := m.cache.get(key); ok {
+		v.Set(reflect.ValueOf(def))
+		return nil
+	}
+
+	var b []byte
+	query := fmt.Sprintf("SELECT json FROM %s WHERE id = ?", table)
+	if err := m.db.QueryRow(query, hashToID(hash)).Scan(&b); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		return err
+	}
+
+	if err := json.Unmarshal(b, dst); err != nil {
+		return err
+	}
+
+	m.cache.add(key, v.Interface())
+	return nil
+}
+
+// DestinyDisplayPropertiesDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-Common-DestinyDisplayPropertiesDefinition.html#schema_Destiny-Definitions-Common-DestinyDisplayPropertiesDefinition
+type DestinyDisplayPropertiesDefinition struct {
+	Description string `json:"description"`
+	Name        string `json:"name"`
+	Icon        string `json:"icon"`
+	HasIcon     bool   `json:"hasIcon"`
+}
+
+// DestinyClassDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyClassDefinition.html#schema_Destiny-Definitions-DestinyClassDefinition
+type DestinyClassDefinition struct {
+	ClassType                      int                                `json:"classType"`
+	DisplayProperties              DestinyDisplayPropertiesDefinition `json:"displayProperties"`
+	GenderedClassNames             map[string]string                  `json:"genderedClassNames"`
+	GenderedClassNamesByGenderHash map[uint]string                    `json:"genderedClassNamesByGenderHash"`
+	Hash                           uint                               `json:"hash"`
+	Index                          int                                `json:"index"`
+	Redacted                       bool                               `json:"redacted"`
+}
+
+// DestinyRaceDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyRaceDefinition.html#schema_Destiny-Definitions-DestinyRaceDefinition
+type DestinyRaceDefinition struct {
+	DisplayProperties             DestinyDisplayPropertiesDefinition `json:"displayProperties"`
+	RaceType                      int                                `json:"raceType"`
+	GenderedRaceNames             map[string]string                  `json:"genderedRaceNames"`
+	GenderedRaceNamesByGenderHash map[uint]string                    `json:"genderedRaceNamesByGenderHash"`
+	Hash                          uint                               `json:"hash"`
+	Index                         int                                `json:"index"`
+	Redacted                      bool                               `json:"redacted"`
+}
+
+// DestinyGenderDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyGenderDefinition.html#schema_Destiny-Definitions-DestinyGenderDefinition
+type DestinyGenderDefinition struct {
+	GenderType        int                                `json:"genderType"`
+	DisplayProperties DestinyDisplayPropertiesDefinition `json:"displayProperties"`
+	Hash              uint                               `json:"hash"`
+	Index             int                                `json:"index"`
+	Redacted          bool                               `json:"redacted"`
+}
+
+// DestinySeasonDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-Seasons-DestinySeasonDefinition.html#schema_Destiny-Definitions-Seasons-DestinySeasonDefinition
+type DestinySeasonDefinition struct {
+	DisplayProperties         DestinyDisplayPropertiesDefinition `json:"displayProperties"`
+	BackgroundImagePath       string                             `json:"backgroundImagePath"`
+	SeasonNumber              int                                `json:"seasonNumber"`
+	StartDate                 *time.Time                         `json:"startDate"`
+	EndDate                   *time.Time                         `json:"endDate"`
+	SeasonPassHash            *uint                              `json:"seasonPassHash"`
+	SeasonPassProgressionHash *uint                              `json:"seasonPassProgressionHash"`
+	ArtifactItemHash          *uint                              `json:"artifactItemHash"`
+	SealPresentationNodeHash  *uint                              `json:"sealPresentationNodeHash"`
+	Hash                      uint                               `json:"hash"`
+	Index                     int                                `json:"index"`
+	Redacted                  bool                               `json:"redacted"`
+}
+
+// DestinyInventoryItemDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyInventoryItemDefinition.html#schema_Destiny-Definitions-DestinyInventoryItemDefinition
+type DestinyInventoryItemDefinition struct {
+	DisplayProperties                 DestinyDisplayPropertiesDefinition   `json:"displayProperties"`
+	CollectibleHash                   *uint                                `json:"collectibleHash"`
+	SecondaryIcon                     string                               `json:"secondaryIcon"`
+	Screenshot                        string                               `json:"screenshot"`
+	ItemTypeDisplayName               string                               `json:"itemTypeDisplayName"`
+	FlavorText                        string                               `json:"flavorText"`
+	ItemTypeAndTierDisplayName        string                               `json:"itemTypeAndTierDisplayName"`
+	Inventory                         *DestinyItemInventoryBlockDefinition `json:"inventory"`
+	Equippable                        bool                                 `json:"equippable"`
+	SummaryItemHash                   *uint                                `json:"summaryItemHash"`
+	AllowActions                      bool                                 `json:"allowActions"`
+	DoesPostmasterPullHaveSideEffects bool                                 `json:"doesPostmasterPullHaveSideEffects"`
+	NonTransferrable                  bool                                 `json:"nonTransferrable"`
+	ItemCategoryHashes                []uint                               `json:"itemCategoryHashes"`
+	SpecialItemType                   int                                  `json:"specialItemType"`
+	ItemType                          int                                  `json:"itemType"`
+	ItemSubType                       int                                  `json:"itemSubType"`
+	ClassType                         int                                  `json:"classType"`
+	BreakerType                       int                                  `json:"breakerType"`
+	BreakerTypeHash                   *uint                                `json:"breakerTypeHash"`
+	DamageTypeHashes                  []uint                               `json:"damageTypeHashes"`
+	DamageTypes                       []int                                `json:"damageTypes"`
+	DefaultDamageType                 int                                  `json:"defaultDamageType"`
+	DefaultDamageTypeHash             *uint                                `json:"defaultDamageTypeHash"`
+	SeasonHash                        *uint                                `json:"seasonHash"`
+	IsWrapper                         bool                                 `json:"isWrapper"`
+	Hash                              uint                                 `json:"hash"`
+	Index                             int                                  `json:"index"`
+	Redacted                          bool                                 `json:"redacted"`
+}
+
+// DestinyItemInventoryBlockDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyItemInventoryBlockDefinition.html#schema_Destiny-Definitions-DestinyItemInventoryBlockDefinition
+type DestinyItemInventoryBlockDefinition struct {
+	StackUniqueLabel         string `json:"stackUniqueLabel"`
+	MaxStackSize             int    `json:"maxStackSize"`
+	BucketTypeHash           uint   `json:"bucketTypeHash"`
+	RecoveryBucketTypeHash   uint   `json:"recoveryBucketTypeHash"`
+	TierTypeHash             uint   `json:"tierTypeHash"`
+	IsInstanceItem           bool   `json:"isInstanceItem"`
+	NonTransferrableOriginal bool   `json:"nonTransferrableOriginal"`
+	TierTypeName             string `json:"tierTypeName"`
+	TierType                 int    `json:"tierType"`
+}
+
+// DestinyActivityDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyActivityDefinition.html#schema_Destiny-Definitions-DestinyActivityDefinition
+type DestinyActivityDefinition struct {
+	DisplayProperties                DestinyDisplayPropertiesDefinition         `json:"displayProperties"`
+	OriginalDisplayProperties        DestinyDisplayPropertiesDefinition         `json:"originalDisplayProperties"`
+	SelectionScreenDisplayProperties DestinyDisplayPropertiesDefinition         `json:"selectionScreenDisplayProperties"`
+	ReleaseIcon                      string                                     `json:"releaseIcon"`
+	ReleaseTime                      int                                        `json:"releaseTime"`
+	ActivityLightLevel               int                                        `json:"activityLightLevel"`
+	DestinationHash                  uint                                       `json:"destinationHash"`
+	PlaceHash                        uint                                       `json:"placeHash"`
+	ActivityTypeHash                 uint                                       `json:"activityTypeHash"`
+	Tier                             int                                        `json:"tier"`
+	PgcrImage                        string                                     `json:"pgcrImage"`
+	IsPlaylist                       bool                                       `json:"isPlaylist"`
+	Matchmaking                      *DestinyActivityMatchmakingBlockDefinition `json:"matchmaking"`
+	DirectActivityModeHash           *uint                                      `json:"directActivityModeHash"`
+	DirectActivityModeType           *int                                       `json:"directActivityModeType"`
+	ActivityModeHashes               []uint                                     `json:"activityModeHashes"`
+	ActivityModeTypes                []int                                      `json:"activityModeTypes"`
+	IsPvP                            bool                                       `json:"isPvP"`
+	Hash                             uint                                       `json:"hash"`
+	Index                            int                                        `json:"index"`
+	Redacted                         bool                                       `json:"redacted"`
+}
+
+// DestinyActivityMatchmakingBlockDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyActivityMatchmakingBlockDefinition.html#schema_Destiny-Definitions-DestinyActivityMatchmakingBlockDefinition
+type DestinyActivityMatchmakingBlockDefinition struct {
+	IsMatchmade          bool `json:"isMatchmade"`
+	MinParty             int  `json:"minParty"`
+	MaxParty             int  `json:"maxParty"`
+	MaxPlayers           int  `json:"maxPlayers"`
+	RequiresGuardianOath bool `json:"requiresGuardianOath"`
+}
+
+// DestinyActivityModeDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Definitions-DestinyActivityModeDefinition.html#schema_Destiny-Definitions-DestinyActivityModeDefinition
+type DestinyActivityModeDefinition struct {
+	DisplayProperties    DestinyDisplayPropertiesDefinition `json:"displayProperties"`
+	PgcrImage            string                             `json:"pgcrImage"`
+	ModeType             DestinyActivityModeType            `json:"modeType"`
+	ActivityModeCategory int                                `json:"activityModeCategory"`
+	IsTeamBased          bool                               `json:"isTeamBased"`
+	Tier                 int                                `json:"tier"`
+	IsAggregateMode      bool                               `json:"isAggregateMode"`
+	ParentHashes         []uint                             `json:"parentHashes"`
+	FriendlyName         string                             `json:"friendlyName"`
+	Display              bool                               `json:"display"`
+	Order                int                                `json:"order"`
+	Hash                 uint                               `json:"hash"`
+	Index                int                                `json:"index"`
+	Redacted             bool                               `json:"redacted"`
+}
+
+// GetClassDefinition gets the class definition with the provided hash.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) GetClassDefinition(hash uint) (DestinyClassDefinition, error) {
+	d := DestinyClassDefinition{}
+	err := m.getDefinition("DestinyClassDefinition", hash, &d)
+	return d, err
+}
+
+// GetRaceDefinition gets the race definition with the provided hash.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) GetRaceDefinition(hash uint) (DestinyRaceDefinition, error) {
+	d := DestinyRaceDefinition{}
+	err := m.getDefinition("DestinyRaceDefinition", hash, &d)
+	return d, err
+}
+
+// GetGenderDefinition gets the gender definition with the provided hash.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) GetGenderDefinition(hash uint) (DestinyGenderDefinition, error) {
+	d := DestinyGenderDefinition{}
+	err := m.getDefinition("DestinyGenderDefinition", hash, &d)
+	return d, err
+}
+
+// GetSeasonDefinition gets the season definition with the provided hash.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) GetSeasonDefinition(hash uint) (DestinySeasonDefinition, error) {
+	d := DestinySeasonDefinition{}
+	err := m.getDefinition("DestinySeasonDefinition", hash, &d)
+	return d, err
+}
+
+// GetInventoryItemDefinition gets the inventory item definition with the provided hash.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) GetInventoryItemDefinition(hash uint) (DestinyInventoryItemDefinition, error) {
+	d := DestinyInventoryItemDefinition{}
+	err := m.getDefinition("DestinyInventoryItemDefinition", hash, &d)
+	return d, err
+}
+
+// GetActivityDefinition gets the activity definition with the provided hash.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) GetActivityDefinition(hash uint) (DestinyActivityDefinition, error) {
+	d := DestinyActivityDefinition{}
+	err := m.getDefinition("DestinyActivityDefinition", hash, &d)
+	return d, err
+}
+
+// GetActivityModeDefinition gets the activity mode definition with the provided hash.
+//
+// If the definition does not exist ErrNotFound is returned
+func (m *Manifest) GetActivityModeDefinition(hash uint) (DestinyActivityModeDefinition, error) {
+	d := DestinyActivityModeDefinition{}
+	err := m.getDefinition("DestinyActivityModeDefinition", hash, &d)
+	return d, err
+}
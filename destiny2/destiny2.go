@@ -1,10 +1,17 @@
 package destiny2
 
 import (
+	"context"
+	"fmt"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Destiny2Service is an interface for interfacing with the destiny2 endpoints
+// of the Bungie API.
+// https://bungie-net.github.io/multi/operation_get_Destiny2-GetProfile.html#operation_get_Destiny2-GetProfile
 type Destiny2Service struct {
 	c *Client
 }
@@ -14,68 +21,195 @@ type Destiny2Service struct {
 type Component int
 
 const (
+	// ComponentNone is not a real component, and is only here for completeness.
+	ComponentNone Component = 0
+
 	// Profiles is the most basic component, only relevant when calling GetProfile. This returns basic information
 	// about the profile, which is almost nothing: a list of characterIds, some information about the last time
 	// you logged in, and that most sobering statistic: how long you've played.
 	Profiles Component = 100
 
+	// VendorReceipts returns information about the profile's recent Vendor purchases that can still be refunded.
+	// Requires authorization.
+	VendorReceipts Component = 101
+
+	// ProfileInventories returns the profile-level inventories, such as the Vault buckets. Requires authorization.
+	ProfileInventories Component = 102
+
+	// ProfileCurrencies returns a "virtual" bucket of the currencies available to the profile.
+	ProfileCurrencies Component = 103
+
+	// ProfileProgression returns progression and checklist information that is tracked at the profile level.
+	ProfileProgression Component = 104
+
+	// PlatformSilver returns the amount of Silver the profile has on each platform.
+	PlatformSilver Component = 105
+
 	// Characters gets summary info about each of the characters in the profile.
 	Characters Component = 200
+
+	// CharacterInventories gets information about the unequipped items on each character. Requires
+	// authorization.
+	CharacterInventories Component = 201
+
+	// CharacterProgressions gets the progression, faction, milestone and quest information for each character.
+	CharacterProgressions Component = 202
+
+	// CharacterRenderData gets the data needed to render each character in 3D.
+	CharacterRenderData Component = 203
+
+	// CharacterActivities gets the activities each character can currently participate in.
+	CharacterActivities Component = 204
+
+	// CharacterEquipment gets information about the equipped items on each character.
+	CharacterEquipment Component = 205
+
+	// ItemInstances returns basic instanced information, such as the primary stat and damage type, for
+	// any instanced items returned by the request.
+	ItemInstances Component = 300
+
+	// ItemObjectives returns the objectives for any items returned by the request.
+	ItemObjectives Component = 301
+
+	// ItemPerks returns the perks for any items returned by the request.
+	ItemPerks Component = 302
+
+	// ItemRenderData returns the data needed to render any items returned by the request.
+	ItemRenderData Component = 303
+
+	// ItemStats returns the stats for any items returned by the request.
+	ItemStats Component = 304
+
+	// ItemSockets returns the socket state for any items returned by the request.
+	ItemSockets Component = 305
+
+	// ItemTalentGrids returns the talent grid state for any items returned by the request.
+	ItemTalentGrids Component = 306
+
+	// ItemCommonData returns the uninstanced information for any items returned by the request.
+	ItemCommonData Component = 307
+
+	// ItemPlugStates returns the state of any plugs for items returned by the request.
+	ItemPlugStates Component = 308
+
+	// ItemPlugObjectives returns the objectives of the plugs socketed into items returned by the request.
+	ItemPlugObjectives Component = 309
+
+	// ItemReusablePlugs returns the reusable plugs available to the sockets of items returned by the request.
+	ItemReusablePlugs Component = 310
+
+	// Vendors returns summary information about vendors. Only relevant when calling vendor endpoints.
+	Vendors Component = 400
+
+	// VendorCategories returns the categories of items sold by vendors. Only relevant when calling
+	// vendor endpoints.
+	VendorCategories Component = 401
+
+	// VendorSales returns the items sold by vendors. Only relevant when calling vendor endpoints.
+	VendorSales Component = 402
+
+	// Kiosks returns the items that can be reclaimed from kiosks, at both the profile and character level.
+	Kiosks Component = 500
+
+	// CurrencyLookups returns a lookup of the currencies each character has, for checking whether items
+	// can be afforded.
+	CurrencyLookups Component = 600
+
+	// PresentationNodes returns the progress of presentation nodes, at both the profile and character level.
+	PresentationNodes Component = 700
+
+	// Collectibles returns the state of collectibles, at both the profile and character level.
+	Collectibles Component = 800
+
+	// Records returns the state of records (triumphs), at both the profile and character level.
+	Records Component = 900
+
+	// Transitory returns data that is only relevant while the profile is online, such as the current
+	// activity and fireteam.
+	Transitory Component = 1000
+
+	// Metrics returns the state of metrics tracked by the profile.
+	Metrics Component = 1100
 )
 
 // DestinyProfileResponse ...
 // https://bungie-net.github.io/multi/schema_Destiny-Responses-DestinyProfileResponse.html#schema_Destiny-Responses-DestinyProfileResponse
 type DestinyProfileResponse struct {
-	Profile    *SingleComponentResponseOfDestinyProfileComponent               `json:"profile"`
-	Characters *DictionaryComponentResponseOfint64AndDestinyCharacterComponent `json:"characters"`
+	VendorReceipts                     *SingleComponentResponseOfDestinyVendorReceiptsComponent                   `json:"vendorReceipts"`
+	ProfileInventory                   *SingleComponentResponseOfDestinyInventoryComponent                        `json:"profileInventory"`
+	ProfileCurrencies                  *SingleComponentResponseOfDestinyInventoryComponent                        `json:"profileCurrencies"`
+	Profile                            *SingleComponentResponseOfDestinyProfileComponent                          `json:"profile"`
+	PlatformSilver                     *SingleComponentResponseOfDestinyPlatformSilverComponent                   `json:"platformSilver"`
+	ProfileKiosks                      *SingleComponentResponseOfDestinyKiosksComponent                           `json:"profileKiosks"`
+	ProfilePlugSets                    *SingleComponentResponseOfDestinyPlugSetsComponent                         `json:"profilePlugSets"`
+	ProfileProgression                 *SingleComponentResponseOfDestinyProfileProgressionComponent               `json:"profileProgression"`
+	ProfilePresentationNodes           *SingleComponentResponseOfDestinyPresentationNodesComponent                `json:"profilePresentationNodes"`
+	ProfileRecords                     *SingleComponentResponseOfDestinyProfileRecordsComponent                   `json:"profileRecords"`
+	ProfileCollectibles                *SingleComponentResponseOfDestinyProfileCollectiblesComponent              `json:"profileCollectibles"`
+	ProfileTransitoryData              *SingleComponentResponseOfDestinyProfileTransitoryComponent                `json:"profileTransitoryData"`
+	Metrics                            *SingleComponentResponseOfDestinyMetricsComponent                          `json:"metrics"`
+	Characters                         *DictionaryComponentResponseOfint64AndDestinyCharacterComponent            `json:"characters"`
+	CharacterInventories               *DictionaryComponentResponseOfint64AndDestinyInventoryComponent            `json:"characterInventories"`
+	CharacterProgressions              *DictionaryComponentResponseOfint64AndDestinyCharacterProgressionComponent `json:"characterProgressions"`
+	CharacterRenderData                *DictionaryComponentResponseOfint64AndDestinyCharacterRenderComponent      `json:"characterRenderData"`
+	CharacterActivities                *DictionaryComponentResponseOfint64AndDestinyCharacterActivitiesComponent  `json:"characterActivities"`
+	CharacterEquipment                 *DictionaryComponentResponseOfint64AndDestinyInventoryComponent            `json:"characterEquipment"`
+	CharacterKiosks                    *DictionaryComponentResponseOfint64AndDestinyKiosksComponent               `json:"characterKiosks"`
+	CharacterPlugSets                  *DictionaryComponentResponseOfint64AndDestinyPlugSetsComponent             `json:"characterPlugSets"`
+	CharacterUninstancedItemComponents map[int64]DestinyBaseItemComponentSetOfuint32                              `json:"characterUninstancedItemComponents"`
+	CharacterPresentationNodes         *DictionaryComponentResponseOfint64AndDestinyPresentationNodesComponent    `json:"characterPresentationNodes"`
+	CharacterRecords                   *DictionaryComponentResponseOfint64AndDestinyCharacterRecordsComponent     `json:"characterRecords"`
+	CharacterCollectibles              *DictionaryComponentResponseOfint64AndDestinyCollectiblesComponent         `json:"characterCollectibles"`
+	CharacterCurrencyLookups           *DictionaryComponentResponseOfint64AndDestinyCurrenciesComponent           `json:"characterCurrencyLookups"`
+	ItemComponents                     *DestinyItemComponentSetOfint64                                            `json:"itemComponents"`
 }
 
 // SingleComponentResponseOfDestinyProfileComponent ...
 // https://bungie-net.github.io/multi/schema_SingleComponentResponseOfDestinyProfileComponent.html#schema_SingleComponentResponseOfDestinyProfileComponent
 type SingleComponentResponseOfDestinyProfileComponent struct {
-	Data    DestinyProfileComponent
-	Privacy int `json:"privacy"`
+	Data    DestinyProfileComponent `json:"data"`
+	Privacy int                     `json:"privacy"`
 }
 
 // DestinyProfileComponent ...
 // https://bungie-net.github.io/multi/schema_Destiny-Entities-Profiles-DestinyProfileComponent.html#schema_Destiny-Entities-Profiles-DestinyProfileComponent
 type DestinyProfileComponent struct {
-	UserInfo       struct{}  `json:"userInfo"`
-	DateLastPlayed time.Time `json:"dateLastPlayed"`
-	VersionsOwned  int       `json:"versionsOwned"`
-	CharacterIds   []int64   `json:"characterIds"`
-	SeasonHashes   []uint    `json:"seasonHashes"`
+	UserInfo       UserInfoCard `json:"userInfo"`
+	DateLastPlayed time.Time    `json:"dateLastPlayed"`
+	VersionsOwned  int          `json:"versionsOwned"`
+	CharacterIds   Int64Slice   `json:"characterIds"`
+	SeasonHashes   []uint       `json:"seasonHashes"`
 }
 
 // DictionaryComponentResponseOfint64AndDestinyCharacterComponent ...
 // https://bungie-net.github.io/multi/schema_DictionaryComponentResponseOfint64AndDestinyCharacterComponent.html#schema_DictionaryComponentResponseOfint64AndDestinyCharacterComponent
 type DictionaryComponentResponseOfint64AndDestinyCharacterComponent struct {
-	Data    map[int64]DestinyCharacterComponent
-	Privacy int `json:"privacy"`
+	Data    map[int64]DestinyCharacterComponent `json:"data"`
+	Privacy int                                 `json:"privacy"`
 }
 
 // DestinyCharacterComponent ...
 // https://bungie-net.github.io/multi/schema_Destiny-Entities-Characters-DestinyCharacterComponent.html#schema_Destiny-Entities-Characters-DestinyCharacterComponent
 type DestinyCharacterComponent struct {
-	MembershipID             int64              `json:"membershipId,string"`
-	MembershipType           int                `json:"membershipType"`
-	CharacterID              int64              `json:"characterId,string"`
-	DateLastPlayed           time.Time          `json:"dateLastPlayed"`
-	MinutesPlayedThisSession int64              `json:"minutesPlayedThisSession,string"`
-	MinutesPlayedTotal       int64              `json:"minutesPlayedTotal,string"`
-	Light                    int                `json:"light"`
-	Stats                    map[uint]int       `json:"stats"`
-	RaceHash                 uint               `json:"raceHash"`
-	GenderHash               uint               `json:"genderHash"`
-	ClassHash                uint               `json:"classHash"`
-	EmblemPath               string             `json:"emblemPath"`
-	EmblemBackgroundPath     string             `json:"emblemBackgroundPath"`
-	EmblemHash               uint               `json:"string"`
-	EmblemColor              DestinyColor       `json:"emblemColor"`
-	LevelProgression         DestinyProgression `json:"levelProgression"`
-	BaseCharacterLevel       int                `json:"baseCharacterLevel"`
-	PercentToNextLevel       float32            `json:"percentToNextLevel"`
-	TitleRecordHash          *uint              `json:"titleRecordHash"`
+	MembershipID             int64                `json:"membershipId,string"`
+	MembershipType           BungieMembershipType `json:"membershipType"`
+	CharacterID              int64                `json:"characterId,string"`
+	DateLastPlayed           time.Time            `json:"dateLastPlayed"`
+	MinutesPlayedThisSession int64                `json:"minutesPlayedThisSession,string"`
+	MinutesPlayedTotal       int64                `json:"minutesPlayedTotal,string"`
+	Light                    int                  `json:"light"`
+	Stats                    map[uint]int         `json:"stats"`
+	RaceHash                 uint                 `json:"raceHash"`
+	GenderHash               uint                 `json:"genderHash"`
+	ClassHash                uint                 `json:"classHash"`
+	EmblemPath               string               `json:"emblemPath"`
+	EmblemBackgroundPath     string               `json:"emblemBackgroundPath"`
+	EmblemHash               uint                 `json:"emblemHash"`
+	EmblemColor              DestinyColor         `json:"emblemColor"`
+	LevelProgression         DestinyProgression   `json:"levelProgression"`
+	BaseCharacterLevel       int                  `json:"baseCharacterLevel"`
+	PercentToNextLevel       float32              `json:"percentToNextLevel"`
+	TitleRecordHash          *uint                `json:"titleRecordHash"`
 }
 
 // DestinyColor ...
@@ -113,11 +247,144 @@ type DestinyProgressionResetEntry struct {
 	Resets int `json:"resets"`
 }
 
-// func (ds *Destiny2Service) GetProfile(membershipType int, membershipID int64, opts ...RequestOption) error {
+// GetProfile returns Destiny Profile information for the supplied membership. Only the sections of the
+// response for the provided components will be populated. At least one component must be provided
+func (ds *Destiny2Service) GetProfile(ctx context.Context, membershipType BungieMembershipType, membershipID int64, components ...Component) (DestinyProfileResponse, error) {
+	return ds.GetProfileWithOptions(ctx, membershipType, membershipID, components)
+}
+
+// GetProfileWithOptions is GetProfile but accepts request options, eg. OptionOAuthToken for components
+// that require authorization
+func (ds *Destiny2Service) GetProfileWithOptions(ctx context.Context, membershipType BungieMembershipType, membershipID int64, components []Component, opts ...RequestOption) (DestinyProfileResponse, error) {
+	r := DestinyProfileResponse{}
+	if len(components) == 0 {
+		return r, ErrNoComponents
+	}
+
+	ints := make([]int, len(components))
+	for i, c := range components {
+		ints[i] = int(c)
+	}
+
+	endpoint := fmt.Sprintf("/%d/Profile/%d", membershipType, membershipID)
+	opts = append([]RequestOption{OptionQuery("components", joinInts(ints))}, opts...)
+	err := ds.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// DestinyLinkedProfilesResponse ...
+// https://bungie-net.github.io/multi/schema_Destiny-Responses-DestinyLinkedProfilesResponse.html#schema_Destiny-Responses-DestinyLinkedProfilesResponse
+type DestinyLinkedProfilesResponse struct {
+	Profiles           []DestinyProfileUserInfoCard `json:"profiles"`
+	BnetMembership     UserInfoCard                 `json:"bnetMembership"`
+	ProfilesWithErrors []DestinyErrorProfile        `json:"profilesWithErrors"`
+}
+
+// DestinyProfileUserInfoCard ...
+// https://bungie-net.github.io/multi/schema_Destiny-Responses-DestinyProfileUserInfoCard.html#schema_Destiny-Responses-DestinyProfileUserInfoCard
+type DestinyProfileUserInfoCard struct {
+	DateLastPlayed              time.Time              `json:"dateLastPlayed"`
+	IsOverridden                bool                   `json:"isOverridden"`
+	IsCrossSavePrimary          bool                   `json:"isCrossSavePrimary"`
+	SupplementalDisplayName     string                 `json:"supplementalDisplayName"`
+	IconPath                    string                 `json:"iconPath"`
+	CrossSaveOverride           BungieMembershipType   `json:"crossSaveOverride"`
+	ApplicableMembershipTypes   []BungieMembershipType `json:"applicableMembershipTypes"`
+	IsPublic                    bool                   `json:"isPublic"`
+	MembershipType              BungieMembershipType   `json:"membershipType"`
+	MembershipID                int64                  `json:"membershipId,string"`
+	DisplayName                 string                 `json:"displayName"`
+	BungieGlobalDisplayName     string                 `json:"bungieGlobalDisplayName"`
+	BungieGlobalDisplayNameCode int                    `json:"bungieGlobalDisplayNameCode"`
+}
+
+// DestinyErrorProfile ...
+// https://bungie-net.github.io/multi/schema_Destiny-Responses-DestinyErrorProfile.html#schema_Destiny-Responses-DestinyErrorProfile
+type DestinyErrorProfile struct {
+	ErrorCode PlatformErrorCode `json:"errorCode"`
+	InfoCard  UserInfoCard      `json:"infoCard"`
+}
+
+// GetLinkedProfiles returns a summary of all the Destiny profiles linked to the given membership,
+// including which one is the cross save primary. This endpoint does not require the user to have
+// authorized the application
+func (ds *Destiny2Service) GetLinkedProfiles(ctx context.Context, membershipType BungieMembershipType, membershipID int64, opts ...RequestOption) (DestinyLinkedProfilesResponse, error) {
+	r := DestinyLinkedProfilesResponse{}
+	endpoint := fmt.Sprintf("/%d/Profile/%d/LinkedProfiles", membershipType, membershipID)
+	err := ds.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// ExactSearchRequest ...
+// https://bungie-net.github.io/multi/schema_User-ExactSearchRequest.html#schema_User-ExactSearchRequest
+type ExactSearchRequest struct {
+	DisplayName     string `json:"displayName"`
+	DisplayNameCode int    `json:"displayNameCode"`
+}
+
+// FormatBungieName formats a Bungie Name and its code in the name#1234 form. An empty string is
+// returned if name is empty
+func FormatBungieName(name string, code int) string {
+	if name == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s#%04d", name, code)
+}
+
+// ParseBungieName splits a Bungie Name in the name#1234 form into the name and its code
+func ParseBungieName(bungieName string) (string, int, error) {
+	i := strings.LastIndex(bungieName, "#")
+	if i <= 0 || i == len(bungieName)-1 {
+		return "", 0, fmt.Errorf("invalid bungie name %q: expected name#1234", bungieName)
+	}
+
+	code, err := strconv.Atoi(bungieName[i+1:])
+	if err != nil || code < 0 {
+		return "", 0, fmt.Errorf("invalid bungie name %q: code must be a number", bungieName)
+	}
+
+	return bungieName[:i], code, nil
+}
 
-// }
+// SearchDestinyPlayerByBungieName searches for the Destiny memberships of the user with the given
+// Bungie Name. Use MembershipTypeAll to search across all membership types
+func (ds *Destiny2Service) SearchDestinyPlayerByBungieName(ctx context.Context, membershipType BungieMembershipType, displayName string, displayNameCode int, opts ...RequestOption) ([]UserInfoCard, error) {
+	body, err := OptionJSONBody(ExactSearchRequest{DisplayName: displayName, DisplayNameCode: displayNameCode})
+	if err != nil {
+		return nil, err
+	}
+
+	r := []UserInfoCard{}
+	endpoint := fmt.Sprintf("/SearchDestinyPlayerByBungieName/%d", membershipType)
+	opts = append([]RequestOption{body}, opts...)
+	err = ds.do(ctx, "POST", endpoint, &r, opts...)
+	return r, err
+}
+
+// FindPlayer searches for a player across all membership types. If query is a Bungie Name in the
+// name#1234 form the exact user is looked up, otherwise the memberships of the first page of users
+// whose Bungie Name starts with query are returned
+func (ds *Destiny2Service) FindPlayer(ctx context.Context, query string, opts ...RequestOption) ([]UserInfoCard, error) {
+	query = strings.TrimSpace(query)
+	if name, code, err := ParseBungieName(query); err == nil {
+		return ds.SearchDestinyPlayerByBungieName(ctx, MembershipTypeAll, name, code, opts...)
+	}
+
+	users, err := ds.c.UserService.SearchByGlobalName(ctx, query, 0, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := []UserInfoCard{}
+	for _, user := range users.SearchResults {
+		cards = append(cards, user.DestinyMemberships...)
+	}
+
+	return cards, nil
+}
 
-func (gs *Destiny2Service) do(method, endpoint string, dst interface{}, opts ...RequestOption) error {
+func (gs *Destiny2Service) do(ctx context.Context, method, endpoint string, dst interface{}, opts ...RequestOption) error {
 	endpoint = path.Join("/Destiny2", endpoint)
-	return gs.c.do(method, endpoint, dst, opts...)
+	return gs.c.do(ctx, method, endpoint, dst, opts...)
 }
@@ -0,0 +1,161 @@
+package destiny2test_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+// activity returns an activity of the provided mode
+func activity(instanceID int64, mode destiny2.DestinyActivityModeType) destiny2.DestinyHistoricalStatsPeriodGroup {
+	return destiny2.DestinyHistoricalStatsPeriodGroup{ActivityDetails: destiny2.DestinyHistoricalStatsActivity{
+		InstanceID: instanceID,
+		Mode:       mode,
+		Modes:      []destiny2.DestinyActivityModeType{mode},
+	}}
+}
+
+func TestGetActivityHistoryQuery(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddActivities(destiny2.MembershipTypeSteam, 1, 2, activity(10, destiny2.ModeRaid), activity(11, destiny2.ModeGambit))
+
+	tests := []struct {
+		mode        destiny2.DestinyActivityModeType
+		page, count int
+		want        url.Values
+		activities  int
+	}{
+		{destiny2.ModeRaid, 0, 50, url.Values{"mode": {"4"}, "page": {"0"}, "count": {"50"}}, 1},
+		{destiny2.ModeRaid, 2, 50, url.Values{"mode": {"4"}, "page": {"2"}, "count": {"50"}}, 0},
+		{destiny2.ModeNone, 0, 0, url.Values{"mode": {"0"}, "page": {"0"}}, 2},
+	}
+	c := s.Client()
+	for _, tt := range tests {
+		history, err := c.Destiny2Service.GetActivityHistory(context.Background(), destiny2.MembershipTypeSteam, 1, 2, tt.mode, tt.page, tt.count)
+		if err != nil {
+			t.Fatalf("GetActivityHistory() error = %v", err)
+		}
+		if len(history.Activities) != tt.activities {
+			t.Errorf("GetActivityHistory(mode %d, page %d, count %d) returned %d activities, want %d", tt.mode, tt.page, tt.count, len(history.Activities), tt.activities)
+		}
+
+		req, _ := s.LastRequest()
+		if want := "/Destiny2/3/Account/1/Character/2/Stats/Activities"; req.Path != want {
+			t.Errorf("GetActivityHistory() requested %s, want %s", req.Path, want)
+		}
+		if req.Query.Encode() != tt.want.Encode() {
+			t.Errorf("GetActivityHistory(mode %d, page %d, count %d) query = %s, want %s", tt.mode, tt.page, tt.count, req.Query.Encode(), tt.want.Encode())
+		}
+	}
+}
+
+func TestGetPostGameCarnageReportStatsHost(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+	stats := destiny2test.NewServer()
+	defer stats.Close()
+
+	report := destiny2.DestinyPostGameCarnageReportData{ActivityDetails: destiny2.DestinyHistoricalStatsActivity{InstanceID: 42}}
+	stats.AddPostGameCarnageReport(report)
+
+	c := s.Client().SetStatsSiteURL(stats.URL)
+	got, err := c.Destiny2Service.GetPostGameCarnageReport(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetPostGameCarnageReport() error = %v", err)
+	}
+	if got.ActivityDetails.InstanceID != 42 {
+		t.Errorf("GetPostGameCarnageReport() returned activity %d, want 42", got.ActivityDetails.InstanceID)
+	}
+
+	path := "/Destiny2/Stats/PostGameCarnageReport/42"
+	if n := stats.Requests(path); n != 1 {
+		t.Errorf("stats host received %d requests, want 1", n)
+	}
+	if n := s.Requests(path); n != 0 {
+		t.Errorf("site received %d requests, want 0", n)
+	}
+}
+
+func TestActivityHistoryPager(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	// Two full pages of 10 activities followed by a page of 3
+	for i := 0; i < 23; i++ {
+		s.AddActivities(destiny2.MembershipTypeSteam, 1, 2, activity(int64(i), destiny2.ModeRaid))
+	}
+
+	p := s.Client().Destiny2Service.NewActivityHistoryPager(destiny2.MembershipTypeSteam, 1, 2, destiny2.ModeNone, 10)
+	total := 0
+	for p.Next(context.Background()) {
+		total += len(p.Activities())
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if total != 23 {
+		t.Errorf("pager returned %d activities, want 23", total)
+	}
+	if n := s.Requests("/Destiny2/3/Account/1/Character/2/Stats/Activities"); n != 3 {
+		t.Errorf("pager made %d requests, want 3", n)
+	}
+	if req, _ := s.LastRequest(); req.Query.Get("page") != "2" {
+		t.Errorf("pager last requested page %s, want 2", req.Query.Get("page"))
+	}
+}
+
+func TestDecodeRecordedActivity(t *testing.T) {
+	rec, err := destiny2test.NewRecorder("testdata/activity.json", destiny2test.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	ctx := context.Background()
+	c := destiny2.NewClient(destiny2test.APIKey).SetHTTPClient(rec.Client()).SetRetryPolicy(destiny2.NoRetries)
+	history, err := c.Destiny2Service.GetActivityHistory(ctx, destiny2.MembershipTypeSteam, 4611686018467284386, 2305843009300000001, destiny2.ModeRaid, 0, 1)
+	if err != nil {
+		t.Fatalf("GetActivityHistory() error = %v", err)
+	}
+	if len(history.Activities) != 1 {
+		t.Fatalf("GetActivityHistory() returned %d activities, want 1", len(history.Activities))
+	}
+	played := history.Activities[0]
+	if played.ActivityDetails.InstanceID != 8574693720 || !played.Completed() || played.Values.Basic("kills") != 187 {
+		t.Errorf("GetActivityHistory() activity = %+v, want completed instance 8574693720 with 187 kills", played)
+	}
+
+	report, err := c.Destiny2Service.GetPostGameCarnageReport(ctx, played.ActivityDetails.InstanceID)
+	if err != nil {
+		t.Fatalf("GetPostGameCarnageReport() error = %v", err)
+	}
+	if len(report.Entries) != 1 {
+		t.Fatalf("GetPostGameCarnageReport() returned %d entries, want 1", len(report.Entries))
+	}
+	entry := report.Entries[0]
+	if entry.CharacterID != 2305843009300000001 || entry.Player.DestinyUserInfo.MembershipID != 4611686018467284386 || !entry.Completed() {
+		t.Errorf("GetPostGameCarnageReport() entry = %+v, want the completed character 2305843009300000001", entry)
+	}
+	if entry.Extended == nil || entry.Extended.Weapons[0].Values.Basic("uniqueWeaponKills") != 96 {
+		t.Errorf("GetPostGameCarnageReport() extended = %+v, want 96 weapon kills", entry.Extended)
+	}
+
+	stats, err := c.Destiny2Service.GetHistoricalStats(ctx, destiny2.MembershipTypeSteam, 4611686018467284386, 0, destiny2.PeriodTypeAllTime,
+		[]destiny2.DestinyActivityModeType{destiny2.ModeRaid}, []destiny2.DestinyStatsGroupType{destiny2.StatsGroupGeneral})
+	if err != nil {
+		t.Fatalf("GetHistoricalStats() error = %v", err)
+	}
+
+	// Only stats from a single activity name the activity they came from
+	allTime := stats["raid"].AllTime
+	if id := allTime["bestSingleGameKills"].ActivityID; id == nil || *id != 8574693720 {
+		t.Errorf("bestSingleGameKills activity = %v, want 8574693720", id)
+	}
+	if id := allTime["kills"].ActivityID; id != nil {
+		t.Errorf("kills activity = %d, want none", *id)
+	}
+}
@@ -0,0 +1,144 @@
+package destiny2test_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+)
+
+// Run with -race, the cache is revalidated by many go routines at once
+func TestCacheRevalidation(t *testing.T) {
+	var full, notModified int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every response is stale right away so every request after the first is revalidated
+		w.Header().Set("Cache-Control", "no-cache")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		atomic.AddInt32(&full, 1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Response":{"profile":{"data":{"characterIds":["1","2"]}}},"ErrorCode":1,"ErrorStatus":"Success"}`))
+	}))
+	defer api.Close()
+
+	// Callers are free to change the headers of the responses they are handed
+	scribble := func(next destiny2.Doer) destiny2.Doer {
+		return destiny2.DoerFunc(func(req *http.Request) (*destiny2.Response, error) {
+			resp, err := next.Do(req)
+			if resp != nil {
+				resp.Header.Set("X-Seen", "true")
+			}
+			return resp, err
+		})
+	}
+
+	c := destiny2.NewClient("key").SetSiteURL(api.URL).SetCache(destiny2.NewMemoryCache(8)).Use(scribble)
+	ctx := context.Background()
+	if _, err := c.Destiny2Service.GetProfile(ctx, destiny2.MembershipTypeSteam, 1, destiny2.Profiles); err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			profile, err := c.Destiny2Service.GetProfile(ctx, destiny2.MembershipTypeSteam, 1, destiny2.Profiles)
+			if err != nil {
+				t.Errorf("GetProfile() error = %v", err)
+				return
+			}
+			if got := len(profile.Profile.Data.CharacterIds); got != 2 {
+				t.Errorf("GetProfile() returned %d characters, want 2", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&full); n != 1 {
+		t.Errorf("server sent %d full responses, want 1", n)
+	}
+	if n := atomic.LoadInt32(&notModified); n == 0 {
+		t.Errorf("cache never revalidated its entry")
+	}
+}
+
+func TestCacheConditionalRequestNotShared(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+
+		// Revalidations are held until the test releases them
+		if r.Header.Get("If-None-Match") != "" {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Response":{},"ErrorCode":1,"ErrorStatus":"Success"}`))
+	}))
+	defer api.Close()
+
+	ctx := context.Background()
+	c := destiny2.NewClient("key").SetSiteURL(api.URL).SetCache(destiny2.NewMemoryCache(1))
+	getProfile := func(membershipID int64) error {
+		_, err := c.Destiny2Service.GetProfile(ctx, destiny2.MembershipTypeSteam, membershipID, destiny2.Profiles)
+		return err
+	}
+
+	if err := getProfile(1); err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+
+	// The stale profile is revalidated while another profile evicts it from the cache, so the next
+	// request for it is unconditional and must not share the revalidation's 304
+	revalidated := make(chan error, 1)
+	go func() { revalidated <- getProfile(1) }()
+	<-started
+	if err := getProfile(2); err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+
+	unconditional := make(chan error, 1)
+	go func() { unconditional <- getProfile(1) }()
+	select {
+	case err := <-unconditional:
+		if err != nil {
+			t.Errorf("GetProfile() unconditional error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("unconditional request waited on the revalidation")
+	}
+	close(release)
+
+	if err := <-revalidated; err != nil {
+		t.Errorf("GetProfile() revalidation error = %v", err)
+	}
+}
+
+func TestNotModifiedWithoutCache(t *testing.T) {
+	c := destiny2.NewClient("key").Use(func(next destiny2.Doer) destiny2.Doer {
+		return destiny2.DoerFunc(func(req *http.Request) (*destiny2.Response, error) {
+			return &destiny2.Response{StatusCode: http.StatusNotModified}, nil
+		})
+	})
+
+	_, err := c.Destiny2Service.GetProfile(context.Background(), destiny2.MembershipTypeSteam, 1, destiny2.Profiles)
+	if err != destiny2.ErrNotModified {
+		t.Errorf("GetProfile() error = %v, want %v", err, destiny2.ErrNotModified)
+	}
+}
@@ -0,0 +1,201 @@
+package destiny2test_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+// syncContent syncs a manifest holding the provided definitions into dir and returns it
+func syncContent(t *testing.T, dir string, tables map[string]map[uint]interface{}) *destiny2.Manifest {
+	t.Helper()
+
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.SetManifest("1", map[string][]byte{"en": worldContent(t, tables)})
+	m, err := s.Client().ManifestService.Sync(context.Background(), dir, "en")
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	return m
+}
+
+// renameItem replaces the inventory item with the provided hash in the database synced into dir with one
+// holding only its hash and the new name
+func renameItem(t *testing.T, dir string, hash uint, name string) {
+	t.Helper()
+
+	item := destiny2.DestinyInventoryItemDefinition{Hash: hash}
+	item.DisplayProperties.Name = name
+	b, _ := json.Marshal(item)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "en", "world.content"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	if _, err = db.Exec("UPDATE DestinyInventoryItemDefinition SET json = ? WHERE id = ?", b, int32(uint32(hash))); err != nil {
+		t.Fatalf("renaming item %d error = %v", hash, err)
+	}
+}
+
+func TestDefinitionLookups(t *testing.T) {
+	dir, remove := tempDir(t)
+	defer remove()
+
+	display := func(name string) destiny2.DestinyDisplayPropertiesDefinition {
+		return destiny2.DestinyDisplayPropertiesDefinition{Name: name}
+	}
+	m := syncContent(t, dir, map[string]map[uint]interface{}{
+		"DestinyClassDefinition":         {671679327: destiny2.DestinyClassDefinition{Hash: 671679327, ClassType: 1, DisplayProperties: display("Hunter")}},
+		"DestinyRaceDefinition":          {898834093: destiny2.DestinyRaceDefinition{Hash: 898834093, RaceType: 2, DisplayProperties: display("Exo")}},
+		"DestinyGenderDefinition":        {3111576190: destiny2.DestinyGenderDefinition{Hash: 3111576190, DisplayProperties: display("Male")}},
+		"DestinySeasonDefinition":        {2809059433: destiny2.DestinySeasonDefinition{Hash: 2809059433, SeasonNumber: 14, DisplayProperties: display("Season of the Splicer")}},
+		"DestinyInventoryItemDefinition": {3628991658: destiny2.DestinyInventoryItemDefinition{Hash: 3628991658, ItemType: 3, DisplayProperties: display("Vigilance Wing")}},
+		"DestinyActivityDefinition":      {2122313384: destiny2.DestinyActivityDefinition{Hash: 2122313384, ActivityLightLevel: 1300, DisplayProperties: display("Last Wish")}},
+		"DestinyActivityModeDefinition":  {2043403989: destiny2.DestinyActivityModeDefinition{Hash: 2043403989, ModeType: destiny2.ModeRaid, DisplayProperties: display("Raid")}},
+	})
+	defer m.Close()
+
+	tests := []struct {
+		table string
+		get   func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error)
+		hash  uint
+		name  string
+	}{
+		{"DestinyClassDefinition", func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error) {
+			d, err := m.GetClassDefinition(hash)
+			return d.DisplayProperties, err
+		}, 671679327, "Hunter"},
+		{"DestinyRaceDefinition", func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error) {
+			d, err := m.GetRaceDefinition(hash)
+			return d.DisplayProperties, err
+		}, 898834093, "Exo"},
+		{"DestinyGenderDefinition", func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error) {
+			d, err := m.GetGenderDefinition(hash)
+			return d.DisplayProperties, err
+		}, 3111576190, "Male"},
+		{"DestinySeasonDefinition", func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error) {
+			d, err := m.GetSeasonDefinition(hash)
+			return d.DisplayProperties, err
+		}, 2809059433, "Season of the Splicer"},
+		{"DestinyInventoryItemDefinition", func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error) {
+			d, err := m.GetInventoryItemDefinition(hash)
+			return d.DisplayProperties, err
+		}, 3628991658, "Vigilance Wing"},
+		{"DestinyActivityDefinition", func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error) {
+			d, err := m.GetActivityDefinition(hash)
+			return d.DisplayProperties, err
+		}, 2122313384, "Last Wish"},
+		{"DestinyActivityModeDefinition", func(hash uint) (destiny2.DestinyDisplayPropertiesDefinition, error) {
+			d, err := m.GetActivityModeDefinition(hash)
+			return d.DisplayProperties, err
+		}, 2043403989, "Raid"},
+	}
+	for _, tt := range tests {
+		got, err := tt.get(tt.hash)
+		if err != nil || got.Name != tt.name {
+			t.Errorf("%s %d = %q, %v, want %q", tt.table, tt.hash, got.Name, err, tt.name)
+		}
+
+		if _, err = tt.get(tt.hash + 1); !errors.Is(err, destiny2.ErrNotFound) {
+			t.Errorf("%s %d error = %v, want %v", tt.table, tt.hash+1, err, destiny2.ErrNotFound)
+		}
+	}
+}
+
+func TestDefinitionCacheKeyedByTable(t *testing.T) {
+	dir, remove := tempDir(t)
+	defer remove()
+
+	m := syncContent(t, dir, map[string]map[uint]interface{}{
+		"DestinyActivityModeDefinition": {2043403989: destiny2.DestinyActivityModeDefinition{Hash: 2043403989, ModeType: destiny2.ModeRaid}},
+		"DestinyClassDefinition":        {2043403989: destiny2.DestinyClassDefinition{Hash: 2043403989, ClassType: 2}},
+	})
+	defer m.Close()
+
+	// The same hash in different tables is cached separately
+	mode, err := m.GetActivityModeDefinition(2043403989)
+	if err != nil || mode.ModeType != destiny2.ModeRaid {
+		t.Errorf("GetActivityModeDefinition() mode = %d, %v, want %d", mode.ModeType, err, destiny2.ModeRaid)
+	}
+	class, err := m.GetClassDefinition(2043403989)
+	if err != nil || class.ClassType != 2 {
+		t.Errorf("GetClassDefinition() class = %d, %v, want 2", class.ClassType, err)
+	}
+}
+
+func TestDefinitionCacheHit(t *testing.T) {
+	dir, remove := tempDir(t)
+	defer remove()
+
+	item := destiny2.DestinyInventoryItemDefinition{Hash: 1, ItemCategoryHashes: []uint{1, 20}}
+	item.DisplayProperties.Name = "Cached"
+	m := syncContent(t, dir, map[string]map[uint]interface{}{"DestinyInventoryItemDefinition": {1: item}})
+	defer m.Close()
+
+	if _, err := m.GetInventoryItemDefinition(1); err != nil {
+		t.Fatalf("GetInventoryItemDefinition() error = %v", err)
+	}
+
+	// Changing the row underneath the manifest shows whether the second lookup went to the database
+	renameItem(t, dir, 1, "Changed")
+	got, err := m.GetInventoryItemDefinition(1)
+	if err != nil {
+		t.Fatalf("GetInventoryItemDefinition() error = %v", err)
+	}
+	if got.DisplayProperties.Name != "Cached" || len(got.ItemCategoryHashes) != 2 {
+		t.Errorf("GetInventoryItemDefinition() = %q %v, want the cached %q [1 20]", got.DisplayProperties.Name, got.ItemCategoryHashes, "Cached")
+	}
+}
+
+func TestDefinitionCacheEviction(t *testing.T) {
+	dir, remove := tempDir(t)
+	defer remove()
+
+	// One more item than the cache can hold
+	items := map[uint]interface{}{}
+	for hash := uint(1); hash <= destiny2.DefaultDefinitionCacheSize+1; hash++ {
+		item := destiny2.DestinyInventoryItemDefinition{Hash: hash}
+		item.DisplayProperties.Name = "Original"
+		items[hash] = item
+	}
+	m := syncContent(t, dir, map[string]map[uint]interface{}{"DestinyInventoryItemDefinition": items})
+	defer m.Close()
+
+	get := func(hash uint) string {
+		t.Helper()
+
+		item, err := m.GetInventoryItemDefinition(hash)
+		if err != nil {
+			t.Fatalf("GetInventoryItemDefinition(%d) error = %v", hash, err)
+		}
+
+		return item.DisplayProperties.Name
+	}
+
+	// Filling the cache, then using item 1 again so item 2 is the least recently used
+	for hash := uint(1); hash <= destiny2.DefaultDefinitionCacheSize; hash++ {
+		get(hash)
+	}
+	get(1)
+	get(destiny2.DefaultDefinitionCacheSize + 1)
+
+	renameItem(t, dir, 1, "Changed")
+	renameItem(t, dir, 2, "Changed")
+	if name := get(1); name != "Original" {
+		t.Errorf("recently used item = %q, want it served from the cache", name)
+	}
+	if name := get(2); name != "Changed" {
+		t.Errorf("least recently used item = %q, want it evicted and read from the database", name)
+	}
+}
@@ -0,0 +1,160 @@
+package destiny2test_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+	"golang.org/x/oauth2"
+)
+
+// addAdminGroup adds group 42 to the server with an admin and a member and returns tokens for each
+func addAdminGroup(s *destiny2test.Server) (admin, member *oauth2.Token) {
+	groupMember := func(membershipID int64, memberType destiny2.GroupMemberType) destiny2.GroupMember {
+		m := destiny2.GroupMember{GroupID: 42, MemberType: memberType}
+		m.DestinyUserInfo.MembershipType = destiny2.MembershipTypeSteam
+		m.DestinyUserInfo.MembershipID = membershipID
+		return m
+	}
+	user := func(membershipID int64) destiny2.UserMembershipData {
+		return destiny2.UserMembershipData{
+			BungieNetUser:      destiny2.GeneralUser{MembershipID: membershipID + 100},
+			DestinyMemberships: []destiny2.GroupUserInfoCard{{MembershipType: destiny2.MembershipTypeSteam, MembershipID: membershipID}},
+		}
+	}
+
+	s.AddGroup(destiny2.GroupResponse{Detail: destiny2.GroupV2{GroupID: 42, Name: "Clan", GroupType: destiny2.GroupTypeClan}})
+	s.AddGroupMembers(42, groupMember(1, destiny2.MemberTypeAdmin), groupMember(2, destiny2.MemberTypeMember))
+
+	return s.IssueToken(user(1)), s.IssueToken(user(2))
+}
+
+func TestGroupAdminRequests(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+	admin, _ := addAdminGroup(s)
+
+	ctx := context.Background()
+	gs := s.Client().WithOAuth2Token(ctx, admin).GroupV2Service
+	steam := destiny2.MembershipTypeSteam
+	memberships := []destiny2.UserMembership{{MembershipType: steam, MembershipID: 3}}
+
+	tests := []struct {
+		name   string
+		call   func() error
+		method string
+		path   string
+		body   string
+	}{
+		{"GetPendingMemberships", func() error { _, err := gs.GetPendingMemberships(ctx, 42); return err },
+			http.MethodGet, "/GroupV2/42/Members/Pending", ""},
+		{"GetInvitedIndividuals", func() error { _, err := gs.GetInvitedIndividuals(ctx, 42); return err },
+			http.MethodGet, "/GroupV2/42/Members/InvitedIndividuals", ""},
+		{"GetBannedMembersOfGroup", func() error { _, err := gs.GetBannedMembersOfGroup(ctx, 42); return err },
+			http.MethodGet, "/GroupV2/42/Banned", ""},
+		{"ApprovePending", func() error { _, err := gs.ApprovePending(ctx, 42, steam, 3, "Welcome"); return err },
+			http.MethodPost, "/GroupV2/42/Members/Approve/3/3", `{"message":"Welcome"}`},
+		{"ApprovePendingForList", func() error { _, err := gs.ApprovePendingForList(ctx, 42, memberships, "Welcome"); return err },
+			http.MethodPost, "/GroupV2/42/Members/ApproveList", `{"memberships":[{"membershipType":3,"membershipId":"3","displayName":"","bungieGlobalDisplayName":"","bungieGlobalDisplayNameCode":0}],"message":"Welcome"}`},
+		{"ApproveAllPending", func() error { _, err := gs.ApproveAllPending(ctx, 42, "Welcome"); return err },
+			http.MethodPost, "/GroupV2/42/Members/ApproveAll", `{"message":"Welcome"}`},
+		{"DenyPendingForList", func() error { _, err := gs.DenyPendingForList(ctx, 42, memberships, "Sorry"); return err },
+			http.MethodPost, "/GroupV2/42/Members/DenyList", `{"memberships":[{"membershipType":3,"membershipId":"3","displayName":"","bungieGlobalDisplayName":"","bungieGlobalDisplayNameCode":0}],"message":"Sorry"}`},
+		{"DenyAllPending", func() error { _, err := gs.DenyAllPending(ctx, 42, "Sorry"); return err },
+			http.MethodPost, "/GroupV2/42/Members/DenyAll", `{"message":"Sorry"}`},
+		{"BanMember", func() error {
+			_, err := gs.BanMember(ctx, 42, steam, 3, destiny2.GroupBanRequest{Comment: "Griefing", Length: 2})
+			return err
+		}, http.MethodPost, "/GroupV2/42/Members/3/3/Ban", `{"comment":"Griefing","length":2}`},
+		{"UnbanMember", func() error { _, err := gs.UnbanMember(ctx, 42, steam, 3); return err },
+			http.MethodPost, "/GroupV2/42/Members/3/3/Unban", ""},
+		{"IndividualGroupInvite", func() error { _, err := gs.IndividualGroupInvite(ctx, 42, steam, 3, "Join us"); return err },
+			http.MethodPost, "/GroupV2/42/Members/IndividualInvite/3/3", `{"message":"Join us"}`},
+		{"IndividualGroupInviteCancel", func() error { _, err := gs.IndividualGroupInviteCancel(ctx, 42, steam, 3); return err },
+			http.MethodPost, "/GroupV2/42/Members/IndividualInviteCancel/3/3", ""},
+		{"EditGroupMembership", func() error {
+			_, err := gs.EditGroupMembership(ctx, 42, steam, 3, destiny2.MemberTypeAdmin)
+			return err
+		}, http.MethodPost, "/GroupV2/42/Members/3/3/SetMembershipType/3", ""},
+		{"KickMember", func() error { _, err := gs.KickMember(ctx, 42, steam, 3); return err },
+			http.MethodPost, "/GroupV2/42/Members/3/3/Kick", ""},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); err != nil {
+			t.Errorf("%s() error = %v", tt.name, err)
+			continue
+		}
+
+		req, _ := s.LastRequest()
+		if req.Method != tt.method || req.Path != tt.path {
+			t.Errorf("%s() requested %s %s, want %s %s", tt.name, req.Method, req.Path, tt.method, tt.path)
+		}
+		if !jsonEqual(req.Body, tt.body) {
+			t.Errorf("%s() sent body %s, want %s", tt.name, req.Body, tt.body)
+		}
+		if req.Header.Get("Authorization") != "Bearer "+admin.AccessToken {
+			t.Errorf("%s() sent Authorization %q, want the admin's token", tt.name, req.Header.Get("Authorization"))
+		}
+	}
+}
+
+// jsonEqual reports whether b holds the same JSON as want. An empty want matches an empty body
+func jsonEqual(b []byte, want string) bool {
+	if want == "" || len(b) == 0 {
+		return want == "" && len(b) == 0
+	}
+
+	var got, expected interface{}
+	if json.Unmarshal(b, &got) != nil || json.Unmarshal([]byte(want), &expected) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(got, expected)
+}
+
+func TestGroupAdminRequiresAdmin(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+	_, member := addAdminGroup(s)
+
+	ctx := context.Background()
+	_, err := s.Client().GroupV2Service.KickMember(ctx, 42, destiny2.MembershipTypeSteam, 2)
+	if !errors.Is(err, destiny2.ErrWebAuthRequired) {
+		t.Errorf("KickMember() without a token error = %v, want %v", err, destiny2.ErrWebAuthRequired)
+	}
+
+	_, err = s.Client().WithOAuth2Token(ctx, member).GroupV2Service.KickMember(ctx, 42, destiny2.MembershipTypeSteam, 2)
+	var apiErr *destiny2.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != destiny2.CodeInsufficientPrivileges {
+		t.Errorf("KickMember() as a member error = %v, want %v", err, destiny2.CodeInsufficientPrivileges)
+	}
+}
+
+func TestKickMember(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+	admin, _ := addAdminGroup(s)
+
+	ctx := context.Background()
+	c := s.Client().WithOAuth2Token(ctx, admin)
+	result, err := c.GroupV2Service.KickMember(ctx, 42, destiny2.MembershipTypeSteam, 2)
+	if err != nil {
+		t.Fatalf("KickMember() error = %v", err)
+	}
+	if result.Group.GroupID != 42 {
+		t.Errorf("KickMember() group = %d, want 42", result.Group.GroupID)
+	}
+
+	members, err := c.GroupV2Service.GetAllMembersOfGroup(ctx, 42)
+	if err != nil {
+		t.Fatalf("GetAllMembersOfGroup() error = %v", err)
+	}
+	if len(members) != 1 || members[0].DestinyUserInfo.MembershipID != 1 {
+		t.Errorf("GetAllMembersOfGroup() after kick = %+v, want only the admin", members)
+	}
+}
@@ -0,0 +1,29 @@
+package destiny2test_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+func TestGetGroupByNameEscapesName(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddGroup(destiny2.GroupResponse{Detail: destiny2.GroupV2{GroupID: 42, Name: "Iron Lords/Old?#", GroupType: destiny2.GroupTypeClan}})
+
+	group, err := s.Client().GroupV2Service.GetGroupByName(context.Background(), "Iron Lords/Old?#", destiny2.GroupTypeClan)
+	if err != nil {
+		t.Fatalf("GetGroupByName() error = %v", err)
+	}
+	if group.Detail.GroupID != 42 {
+		t.Errorf("GetGroupByName() returned group %d, want 42", group.Detail.GroupID)
+	}
+
+	req, _ := s.LastRequest()
+	if want := "/GroupV2/Name/Iron%20Lords%2FOld%3F%23/1"; req.Path != want {
+		t.Errorf("GetGroupByName() requested %s, want %s", req.Path, want)
+	}
+}
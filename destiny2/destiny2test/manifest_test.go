@@ -0,0 +1,201 @@
+package destiny2test_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+// worldContent builds a world content database holding the provided definitions, keyed by table and hash
+func worldContent(t *testing.T, tables map[string]map[uint]interface{}) []byte {
+	t.Helper()
+
+	dir, remove := tempDir(t)
+	defer remove()
+
+	path := filepath.Join(dir, "world.content")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	for table, defs := range tables {
+		if _, err = db.Exec("CREATE TABLE " + table + " (id INTEGER PRIMARY KEY NOT NULL, json BLOB)"); err != nil {
+			t.Fatalf("creating %s error = %v", table, err)
+		}
+
+		// Bungie stores hashes as signed 32 bit ints
+		for hash, def := range defs {
+			b, _ := json.Marshal(def)
+			if _, err = db.Exec("INSERT INTO "+table+" (id, json) VALUES (?, ?)", int32(uint32(hash)), b); err != nil {
+				t.Fatalf("inserting into %s error = %v", table, err)
+			}
+		}
+	}
+	if err = db.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	return b
+}
+
+// itemContent builds a world content database holding a single inventory item with the provided name
+func itemContent(t *testing.T, hash uint, name string) []byte {
+	item := destiny2.DestinyInventoryItemDefinition{Hash: hash}
+	item.DisplayProperties.Name = name
+
+	return worldContent(t, map[string]map[uint]interface{}{
+		"DestinyInventoryItemDefinition": {hash: item},
+	})
+}
+
+// tempDir creates a temporary directory and returns it with a function that removes it
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "destiny2test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// syncItemName syncs the manifest into dir and returns the name of the inventory item with the provided hash
+func syncItemName(t *testing.T, c *destiny2.Client, dir string, hash uint) string {
+	t.Helper()
+
+	m, err := c.ManifestService.Sync(context.Background(), dir, "en")
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	defer m.Close()
+
+	item, err := m.GetInventoryItemDefinition(hash)
+	if err != nil {
+		t.Fatalf("GetInventoryItemDefinition() error = %v", err)
+	}
+
+	return item.DisplayProperties.Name
+}
+
+func TestManifestSync(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	manifest := s.SetManifest("1", map[string][]byte{"en": itemContent(t, 3628991658, "Vigilance Wing")})
+	contentPath := manifest.MobileWorldContentPaths["en"]
+
+	// The download must not leak the API key or the user's token to the CDN
+	ctx := context.Background()
+	token := s.IssueToken(destiny2.UserMembershipData{BungieNetUser: destiny2.GeneralUser{MembershipID: 1}})
+	c := s.Client().WithOAuth2Token(ctx, token)
+	dir, remove := tempDir(t)
+	defer remove()
+	if name := syncItemName(t, c, dir, 3628991658); name != "Vigilance Wing" {
+		t.Errorf("GetInventoryItemDefinition() name = %q, want %q", name, "Vigilance Wing")
+	}
+
+	req, _ := s.LastRequest()
+	if req.Path != contentPath {
+		t.Fatalf("Sync() last requested %s, want %s", req.Path, contentPath)
+	}
+	if req.Header.Get("X-API-Key") != "" || req.Header.Get("Authorization") != "" {
+		t.Errorf("Sync() downloaded with headers %v, want no credentials", req.Header)
+	}
+
+	// The copy on disk is reused while the version is unchanged
+	syncItemName(t, c, dir, 3628991658)
+	if n := s.Requests(contentPath); n != 1 {
+		t.Errorf("Sync() downloaded the database %d times, want 1", n)
+	}
+	if n := s.Requests("/Destiny2/Manifest"); n != 2 {
+		t.Errorf("Sync() requested the manifest %d times, want 2", n)
+	}
+}
+
+func TestManifestSyncVersionChange(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	dir, remove := tempDir(t)
+	defer remove()
+	s.SetManifest("1", map[string][]byte{"en": itemContent(t, 1, "Old")})
+	if name := syncItemName(t, c, dir, 1); name != "Old" {
+		t.Fatalf("GetInventoryItemDefinition() name = %q, want %q", name, "Old")
+	}
+
+	manifest := s.SetManifest("2", map[string][]byte{"en": itemContent(t, 1, "New")})
+	if name := syncItemName(t, c, dir, 1); name != "New" {
+		t.Errorf("GetInventoryItemDefinition() after a version change name = %q, want %q", name, "New")
+	}
+	if n := s.Requests(manifest.MobileWorldContentPaths["en"]); n != 1 {
+		t.Errorf("Sync() downloaded the new version %d times, want 1", n)
+	}
+
+	version, err := ioutil.ReadFile(filepath.Join(dir, "en", "version"))
+	if err != nil || string(version) != "2" {
+		t.Errorf("version file = %q, %v, want %q", version, err, "2")
+	}
+}
+
+func TestManifestSyncRedownloadsMissingDatabase(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	dir, remove := tempDir(t)
+	defer remove()
+	manifest := s.SetManifest("1", map[string][]byte{"en": itemContent(t, 1, "Item")})
+	syncItemName(t, c, dir, 1)
+
+	if err := os.Remove(filepath.Join(dir, "en", "world.content")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	syncItemName(t, c, dir, 1)
+	if n := s.Requests(manifest.MobileWorldContentPaths["en"]); n != 2 {
+		t.Errorf("Sync() downloaded the database %d times, want 2", n)
+	}
+}
+
+func TestManifestSyncDownloadError(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	dir, remove := tempDir(t)
+	defer remove()
+	manifest := s.SetManifest("1", map[string][]byte{"en": itemContent(t, 1, "Item")})
+	contentPath := manifest.MobileWorldContentPaths["en"]
+	s.FailWith(contentPath, http.StatusNotFound, destiny2.APIResponse{}, 1)
+
+	_, err := c.ManifestService.Sync(context.Background(), dir, "en")
+	var apiErr *destiny2.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Sync() error = %v, want a %d", err, http.StatusNotFound)
+	}
+
+	// A failed download must not be mistaken for an up to date copy
+	syncItemName(t, c, dir, 1)
+	if n := s.Requests(contentPath); n != 2 {
+		t.Errorf("Sync() downloaded the database %d times, want 2", n)
+	}
+
+	if _, err = c.ManifestService.Sync(context.Background(), dir, "fr"); err == nil {
+		t.Errorf("Sync() for a missing language error = nil, want an error")
+	}
+}
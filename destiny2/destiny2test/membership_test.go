@@ -0,0 +1,136 @@
+package destiny2test_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+func TestPrimaryMembership(t *testing.T) {
+	xbox := destiny2.UserInfoCard{MembershipType: destiny2.MembershipTypeXbox, MembershipID: 1}
+	steam := destiny2.UserInfoCard{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 3}
+
+	// crossSaved returns the card with cross save overriding it with the provided membership type
+	crossSaved := func(card destiny2.UserInfoCard, override destiny2.BungieMembershipType) destiny2.UserInfoCard {
+		card.CrossSaveOverride = override
+		return card
+	}
+
+	tests := map[string]struct {
+		cards []destiny2.UserInfoCard
+		want  int64
+		ok    bool
+	}{
+		"no memberships":                     {nil, 0, false},
+		"single membership":                  {[]destiny2.UserInfoCard{steam}, 3, true},
+		"multiple memberships":               {[]destiny2.UserInfoCard{xbox, steam}, 0, false},
+		"cross save":                         {[]destiny2.UserInfoCard{crossSaved(xbox, destiny2.MembershipTypeSteam), crossSaved(steam, destiny2.MembershipTypeSteam)}, 3, true},
+		"cross save on one card":             {[]destiny2.UserInfoCard{xbox, crossSaved(steam, destiny2.MembershipTypeXbox)}, 1, true},
+		"override missing single":            {[]destiny2.UserInfoCard{crossSaved(steam, destiny2.MembershipTypePSN)}, 3, true},
+		"override missing multiple":          {[]destiny2.UserInfoCard{crossSaved(xbox, destiny2.MembershipTypePSN), crossSaved(steam, destiny2.MembershipTypePSN)}, 0, false},
+		"single membership with no override": {[]destiny2.UserInfoCard{crossSaved(xbox, destiny2.MembershipTypeNone)}, 1, true},
+	}
+	for name, tt := range tests {
+		got, ok := destiny2.PrimaryMembership(tt.cards)
+		if ok != tt.ok || got.MembershipID != tt.want {
+			t.Errorf("%s: PrimaryMembership() = %d, %t, want %d, %t", name, got.MembershipID, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLinkedProfilesPrimaryMembership(t *testing.T) {
+	linked := destiny2.DestinyLinkedProfilesResponse{Profiles: []destiny2.DestinyProfileUserInfoCard{
+		{MembershipType: destiny2.MembershipTypeXbox, MembershipID: 1},
+		{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 3, IsCrossSavePrimary: true},
+	}}
+
+	got, ok := linked.PrimaryMembership()
+	if !ok || got.MembershipID != 3 {
+		t.Errorf("PrimaryMembership() = %d, %t, want 3, true", got.MembershipID, ok)
+	}
+}
+
+func TestUserMembershipDataPrimaryMembership(t *testing.T) {
+	data := destiny2.UserMembershipData{
+		PrimaryMembershipID: 3,
+		DestinyMemberships: []destiny2.GroupUserInfoCard{
+			{MembershipType: destiny2.MembershipTypeXbox, MembershipID: 1},
+			{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 3},
+		},
+	}
+
+	got, ok := data.PrimaryMembership()
+	if !ok || got.MembershipID != 3 {
+		t.Errorf("PrimaryMembership() = %d, %t, want 3, true", got.MembershipID, ok)
+	}
+}
+
+func TestParseBungieMembershipType(t *testing.T) {
+	tests := map[string]destiny2.BungieMembershipType{
+		"3":          destiny2.MembershipTypeSteam,
+		"-1":         destiny2.MembershipTypeAll,
+		"PSN":        destiny2.MembershipTypePSN,
+		" pc ":       destiny2.MembershipTypeSteam,
+		"Battle.net": destiny2.MembershipTypeBlizzard,
+		"epic games": destiny2.MembershipTypeEpic,
+		"TigerXbox":  destiny2.MembershipTypeXbox,
+		"bungie":     destiny2.MembershipTypeBungieNext,
+	}
+	for s, want := range tests {
+		got, err := destiny2.ParseBungieMembershipType(s)
+		if err != nil || got != want {
+			t.Errorf("ParseBungieMembershipType(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+
+	for _, s := range []string{"", "7", "switch"} {
+		if _, err := destiny2.ParseBungieMembershipType(s); err == nil {
+			t.Errorf("ParseBungieMembershipType(%q) error = nil, want an error", s)
+		}
+	}
+}
+
+func TestDecodeRecordedMemberships(t *testing.T) {
+	rec, err := destiny2test.NewRecorder("testdata/membership.json", destiny2test.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	ctx := context.Background()
+	c := destiny2.NewClient(destiny2test.APIKey).SetHTTPClient(rec.Client()).SetRetryPolicy(destiny2.NoRetries)
+	linked, err := c.Destiny2Service.GetLinkedProfiles(ctx, destiny2.MembershipTypeSteam, 4611686018467284386)
+	if err != nil {
+		t.Fatalf("GetLinkedProfiles() error = %v", err)
+	}
+	if len(linked.Profiles) != 1 {
+		t.Fatalf("GetLinkedProfiles() returned %d profiles, want 1", len(linked.Profiles))
+	}
+	profile := linked.Profiles[0]
+	played := time.Date(2021, time.March, 2, 21, 4, 37, 0, time.UTC)
+	if profile.MembershipID != 4611686018467284386 || !profile.IsCrossSavePrimary || !profile.DateLastPlayed.Equal(played) {
+		t.Errorf("GetLinkedProfiles() profile = %+v, want the cross save primary 4611686018467284386 last played %v", profile, played)
+	}
+	if linked.BnetMembership.MembershipID != 14598345 || linked.BnetMembership.MembershipType != destiny2.MembershipTypeBungieNext {
+		t.Errorf("GetLinkedProfiles() Bungie.net membership = %+v, want 14598345", linked.BnetMembership)
+	}
+	if primary, ok := linked.PrimaryMembership(); !ok || primary.BungieName() != "Guardian#1234" {
+		t.Errorf("PrimaryMembership() = %q, %t, want Guardian#1234, true", primary.BungieName(), ok)
+	}
+
+	data, err := c.UserService.GetMembershipDataByID(ctx, 4611686018467284386, destiny2.MembershipTypeAll)
+	if err != nil {
+		t.Fatalf("GetMembershipDataByID() error = %v", err)
+	}
+	if data.BungieNetUser.MembershipID != 14598345 || data.BungieNetUser.SuccessMessageFlags != 8 || data.BungieNetUser.FirstAccess == nil {
+		t.Errorf("GetMembershipDataByID() user = %+v, want 14598345", data.BungieNetUser)
+	}
+	if len(data.DestinyMemberships) != 2 || data.DestinyMemberships[1].MembershipID != 4611686018429000001 {
+		t.Fatalf("GetMembershipDataByID() memberships = %+v, want Steam and Xbox", data.DestinyMemberships)
+	}
+	if primary, ok := data.PrimaryMembership(); !ok || primary.MembershipID != data.PrimaryMembershipID || primary.MembershipType != destiny2.MembershipTypeSteam {
+		t.Errorf("PrimaryMembership() = %d, %t, want the Steam membership %d", primary.MembershipID, ok, data.PrimaryMembershipID)
+	}
+}
@@ -0,0 +1,133 @@
+package destiny2test_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+// pageQuery returns the query params the options set on a request
+func pageQuery(opts []destiny2.RequestOption) url.Values {
+	req, _ := http.NewRequest("GET", "https://www.bungie.net/Platform/GroupV2/42/Members/", nil)
+	for _, opt := range opts {
+		req = opt(req)
+	}
+
+	return req.URL.Query()
+}
+
+func TestPagerStopsAtLastPage(t *testing.T) {
+	var queries []url.Values
+	fetch := func(ctx context.Context, opts ...destiny2.RequestOption) (destiny2.SearchResult, error) {
+		queries = append(queries, pageQuery(opts))
+		page := len(queries)
+
+		return destiny2.SearchResult{HasMore: page < 3, ReplacementContinuationToken: "token" + strconv.Itoa(page)}, nil
+	}
+
+	// The caller's page is replaced by the pager's
+	p := destiny2.NewPager(fetch, destiny2.OptionSetQuery("currentPage", 9)).SetPageSize(25)
+	pages := 0
+	for p.Next(context.Background()) {
+		pages++
+		if p.Page() != pages {
+			t.Errorf("Page() = %d, want %d", p.Page(), pages)
+		}
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if pages != 3 {
+		t.Fatalf("pager returned %d pages, want 3", pages)
+	}
+
+	want := []url.Values{
+		{"currentPage": {"1"}, "itemsPerPage": {"25"}},
+		{"currentPage": {"2"}, "itemsPerPage": {"25"}, "requestContinuationToken": {"token1"}},
+		{"currentPage": {"3"}, "itemsPerPage": {"25"}, "requestContinuationToken": {"token2"}},
+	}
+	for i, q := range queries {
+		if q.Encode() != want[i].Encode() {
+			t.Errorf("page %d query = %s, want %s", i+1, q.Encode(), want[i].Encode())
+		}
+	}
+
+	// A finished pager does not fetch again
+	if p.Next(context.Background()) || len(queries) != 3 {
+		t.Errorf("Next() after the last page fetched page %d", len(queries))
+	}
+}
+
+func TestPagerPropagatesError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	calls := 0
+	fetch := func(ctx context.Context, opts ...destiny2.RequestOption) (destiny2.SearchResult, error) {
+		calls++
+		if calls == 2 {
+			return destiny2.SearchResult{}, errFetch
+		}
+
+		return destiny2.SearchResult{HasMore: true}, nil
+	}
+
+	p := destiny2.NewPager(fetch)
+	if !p.Next(context.Background()) {
+		t.Fatalf("Next() = false, want the first page; Err() = %v", p.Err())
+	}
+	if p.Next(context.Background()) {
+		t.Fatalf("Next() = true, want the failed page to stop the pager")
+	}
+	if !errors.Is(p.Err(), errFetch) {
+		t.Errorf("Err() = %v, want %v", p.Err(), errFetch)
+	}
+
+	// The pager stays stopped even though the endpoint said there was more
+	if p.Next(context.Background()) || calls != 2 {
+		t.Errorf("Next() after an error fetched again, %d calls", calls)
+	}
+}
+
+func TestPagerContextCancelled(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		m := destiny2.GroupMember{GroupID: 42}
+		m.DestinyUserInfo.MembershipID = int64(i)
+		s.AddGroupMembers(42, m)
+	}
+
+	c := s.Client()
+	var page destiny2.SearchResultOfGroupMember
+	p := destiny2.NewPager(func(ctx context.Context, opts ...destiny2.RequestOption) (destiny2.SearchResult, error) {
+		var err error
+		page, err = c.GroupV2Service.GetMembersOfGroup(ctx, 42, opts...)
+		return page.SearchResult, err
+	}).SetPageSize(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if !p.Next(ctx) {
+		t.Fatalf("Next() = false, want the first page; Err() = %v", p.Err())
+	}
+	if len(page.Results) != 2 {
+		t.Errorf("first page has %d members, want 2", len(page.Results))
+	}
+
+	cancel()
+	if p.Next(ctx) {
+		t.Errorf("Next() = true, want the cancelled context to stop the pager")
+	}
+	if !errors.Is(p.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want %v", p.Err(), context.Canceled)
+	}
+	if n := s.Requests("/GroupV2/42/Members"); n != 1 {
+		t.Errorf("pager made %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,88 @@
+package destiny2test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+// waitNow waits on the limiter, giving up if a request is not allowed right away
+func waitNow(l *destiny2.RateLimiter) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	return l.Wait(ctx)
+}
+
+func TestRateLimiterBurstCapped(t *testing.T) {
+	l := destiny2.NewRateLimiter(100, 2)
+
+	// Idling long enough to generate 10 tokens only fills the bucket up to the burst
+	time.Sleep(100 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if err := waitNow(l); err != nil {
+			t.Fatalf("Wait() %d error = %v", i, err)
+		}
+	}
+	if err := waitNow(l); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() past the burst error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := destiny2.NewRateLimiter(0.001, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	// The next token is over 15 minutes away
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	start := time.Now()
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Wait() returned %v after it was cancelled", elapsed)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l := destiny2.NewRateLimiter(0.001, 1)
+	if err := waitNow(l); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	l.SetLimit(0, 1)
+	for i := 0; i < 100; i++ {
+		if err := waitNow(l); err != nil {
+			t.Fatalf("Wait() %d with the limit disabled error = %v", i, err)
+		}
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddGroupMembers(42, destiny2.GroupMember{})
+	c := s.Client().SetRateLimit(0.001, 1)
+	if _, err := c.GroupV2Service.GetMembersOfGroup(context.Background(), 42); err != nil {
+		t.Fatalf("GetMembersOfGroup() error = %v", err)
+	}
+
+	// The second request is held by the limiter and never reaches the server
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.GroupV2Service.GetMembersOfGroup(ctx, 42); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetMembersOfGroup() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := s.Requests("/GroupV2/42/Members"); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,354 @@
+package destiny2test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Mode determines whether a Recorder records or replays responses
+type Mode int
+
+const (
+
+	// ModeReplay replays responses from the fixture file and never sends requests
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests and records the responses so they can be saved to the fixture file
+	ModeRecord
+
+	// ModeReplayOrRecord replays responses if the fixture file exists and records them otherwise
+	ModeReplayOrRecord
+)
+
+// Redacted replaces secrets in recorded fixtures
+const Redacted = "REDACTED"
+
+var (
+	// redactedHeaders are the headers whose values are never written to fixtures
+	redactedHeaders = []string{"X-Api-Key", "Authorization", "Cookie", "Set-Cookie"}
+
+	// redactedFields are the form and JSON fields whose values are never written to fixtures
+	redactedFields = []string{"access_token", "refresh_token", "code", "client_secret"}
+)
+
+// ErrNoFixture is returned by a replaying Recorder when no recorded response matches a request
+var ErrNoFixture = errors.New("destiny2test: no recorded response matches request")
+
+// Interaction is a single recorded request and response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request that is recorded
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the part of a response that is recorded
+type RecordedResponse struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header"`
+	Body       json.RawMessage `json:"body,omitempty"`
+
+	// RawBody holds the body of responses that are not JSON
+	RawBody string `json:"rawBody,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records responses from the Bungie API to a fixture file and
+// replays them later. API keys and OAuth tokens are redacted before they are recorded.
+//
+//	rec, err := destiny2test.NewRecorder("testdata/profile.json", destiny2test.ModeReplayOrRecord, nil)
+//	client.SetHTTPClient(rec.Client())
+//	defer rec.Save()
+type Recorder struct {
+	mu           sync.Mutex
+	path         string
+	mode         Mode
+	transport    http.RoundTripper
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder creates a recorder that uses the fixture file at path. transport is used to send requests
+// when recording, http.DefaultTransport is used if it is nil
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+	}
+
+	if mode == ModeReplayOrRecord {
+		r.mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, err
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// Client returns an http client that uses the recorder as its transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Recording returns true if the recorder is recording responses instead of replaying them
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body is swapped out on a clone so the caller's request is left untouched
+	req = req.Clone(req.Context())
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	recReq := RecordedRequest{
+		Method: req.Method,
+		URL:    redactURL(req.URL),
+		Header: redactHeader(req.Header),
+		Body:   redactRequestBody(reqBody),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recReq)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, Interaction{
+		Request:  recReq,
+		Response: recordResponse(resp, respBody),
+	})
+
+	return resp, nil
+}
+
+// replay returns the first unused recorded response for a request matching req
+func (r *Recorder) replay(req *http.Request, recReq RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request.Method != recReq.Method || in.Request.URL != recReq.URL {
+			continue
+		}
+		r.used[i] = true
+
+		body := []byte(in.Response.Body)
+		if in.Response.RawBody != "" {
+			body = []byte(in.Response.RawBody)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoFixture, recReq.Method, recReq.URL)
+}
+
+// Save writes the recorded responses to the fixture file. Save does nothing if the recorder is replaying
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.path, b, 0644)
+}
+
+// readBody reads the body and replaces it with a copy so it can be read again
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range redactedHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, Redacted)
+		}
+	}
+
+	return h
+}
+
+func redactURL(u *url.URL) string {
+	c := *u
+	q := c.Query()
+	for _, name := range redactedFields {
+		if q.Get(name) != "" {
+			q.Set(name, Redacted)
+		}
+	}
+	c.RawQuery = q.Encode()
+
+	return c.String()
+}
+
+// redactRequestBody redacts secrets from a JSON or form encoded request body
+func redactRequestBody(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if json.Valid(b) {
+		return string(redactJSON(b))
+	}
+
+	return redactForm(b)
+}
+
+func redactForm(b []byte) string {
+
+	form, err := url.ParseQuery(string(b))
+	if err != nil {
+		return string(b)
+	}
+	for _, name := range redactedFields {
+		if form.Get(name) != "" {
+			form.Set(name, Redacted)
+		}
+	}
+
+	return form.Encode()
+}
+
+// recordResponse creates a recorded response with secrets redacted from the response's headers and body
+func recordResponse(resp *http.Response, body []byte) RecordedResponse {
+	rec := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     redactHeader(resp.Header),
+	}
+
+	if json.Valid(body) {
+		rec.Body = redactJSON(body)
+	} else {
+		rec.RawBody = string(body)
+	}
+
+	return rec
+}
+
+// redactJSON redacts secrets from every object in a JSON document, however deeply it is nested
+func redactJSON(b []byte) json.RawMessage {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return b
+	}
+	if !redactValue(v) {
+		return b
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+
+	return out
+}
+
+// redactValue redacts secrets from v and the values nested in it. True is returned if anything was redacted
+func redactValue(v interface{}) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if isRedactedField(key) {
+				v[key] = Redacted
+				found = true
+				continue
+			}
+			found = redactValue(child) || found
+		}
+	case []interface{}:
+		for _, child := range v {
+			found = redactValue(child) || found
+		}
+	}
+
+	return found
+}
+
+func isRedactedField(name string) bool {
+	for _, field := range redactedFields {
+		if name == field {
+			return true
+		}
+	}
+
+	return false
+}
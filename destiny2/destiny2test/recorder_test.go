@@ -0,0 +1,155 @@
+package destiny2test_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+func TestRecorderReplay(t *testing.T) {
+	rec, err := destiny2test.NewRecorder("testdata/profile.json", destiny2test.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	ctx := context.Background()
+	c := destiny2.NewClient(destiny2test.APIKey).SetHTTPClient(rec.Client()).SetRetryPolicy(destiny2.NoRetries)
+	profile, err := c.Destiny2Service.GetProfile(ctx, destiny2.MembershipTypeSteam, 4611686018467284386, destiny2.Profiles)
+	if err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+	if got := len(profile.Profile.Data.CharacterIds); got != 3 {
+		t.Errorf("GetProfile() returned %d characters, want 3", got)
+	}
+
+	// Every recorded response is only replayed once
+	_, err = c.Destiny2Service.GetProfile(ctx, destiny2.MembershipTypeSteam, 4611686018467284386, destiny2.Profiles)
+	if !errors.Is(err, destiny2test.ErrNoFixture) {
+		t.Errorf("GetProfile() replayed twice error = %v, want %v", err, destiny2test.ErrNoFixture)
+	}
+}
+
+func TestRecorderRedactsSecrets(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	code := s.AddUser(destiny2.UserMembershipData{BungieNetUser: destiny2.GeneralUser{MembershipID: 99}})
+	fixture, cleanup := tempFixture(t)
+	defer cleanup()
+
+	rec, err := destiny2test.NewRecorder(fixture, destiny2test.ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	ctx := context.Background()
+	c := s.Client().SetHTTPClient(rec.Client())
+	token, err := c.Exchange(ctx, code)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if _, err = c.WithOAuth2Token(ctx, token).UserService.GetMembershipDataForCurrentUser(ctx); err != nil {
+		t.Fatalf("GetMembershipDataForCurrentUser() error = %v", err)
+	}
+	if err = rec.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	b, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, secret := range map[string]string{
+		"API key":       destiny2test.APIKey,
+		"client secret": destiny2test.ClientSecret,
+		"code":          code,
+		"access token":  token.AccessToken,
+		"refresh token": token.RefreshToken,
+	} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("fixture contains the %s", name)
+		}
+	}
+}
+
+func TestRecorderRedactsNestedJSON(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Response":{"tokens":[{"access_token":"nested-secret","expires_in":3600}]},"ErrorCode":1}`))
+	}))
+	defer api.Close()
+
+	fixture, cleanup := tempFixture(t)
+	defer cleanup()
+
+	rec, err := destiny2test.NewRecorder(fixture, destiny2test.ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	body := `{"credentials":{"refresh_token":"request-secret"}}`
+	resp, err := rec.Client().Post(api.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if err = rec.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	b, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"nested-secret", "request-secret"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("fixture contains %q", secret)
+		}
+	}
+	if !strings.Contains(string(b), "3600") {
+		t.Errorf("fixture is missing fields that are not secret")
+	}
+}
+
+func TestRecorderLeavesRequestUntouched(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer api.Close()
+
+	rec, err := destiny2test.NewRecorder("", destiny2test.ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	req, err := http.NewRequest("POST", api.URL, strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := req.Body
+
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+	if req.Body != body {
+		t.Errorf("RoundTrip() replaced the body of the caller's request")
+	}
+}
+
+// tempFixture returns the path of a fixture file in a temporary directory and a function that removes it
+func tempFixture(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "destiny2test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Join(dir, "fixture.json"), func() { os.RemoveAll(dir) }
+}
@@ -0,0 +1,131 @@
+package destiny2test_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+// fastRetries retries requests without waiting long unless Bungie asks the client to
+var fastRetries = destiny2.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	ctx := context.Background()
+	s.Fail("/User/Search", destiny2.CodeExternalServiceTimeout, 1)
+	c := s.Client().SetRetryPolicy(fastRetries)
+	if _, err := c.UserService.SearchByGlobalName(ctx, "Guard", 0); !destiny2.IsRetryable(err) {
+		t.Errorf("SearchByGlobalName() error = %v, want a retryable error", err)
+	}
+	if n := s.Requests("/User/Search/GlobalName/0"); n != 1 {
+		t.Errorf("SearchByGlobalName() made %d requests, want 1", n)
+	}
+
+	// POSTs are only retried when the policy allows it
+	policy := fastRetries
+	policy.RetryNonIdempotent = true
+	s.Fail("/User/Search", destiny2.CodeExternalServiceTimeout, 1)
+	if _, err := c.UserService.SearchByGlobalName(ctx, "Guard", 0, destiny2.OptionRetryPolicy(policy)); err != nil {
+		t.Errorf("SearchByGlobalName() error = %v", err)
+	}
+	if n := s.Requests("/User/Search/GlobalName/0"); n != 3 {
+		t.Errorf("SearchByGlobalName() made %d requests, want 3", n)
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	// Dropping GetBody so the client has to buffer the body itself to send it again
+	noGetBody := func(req *http.Request) *http.Request {
+		req.GetBody = nil
+		return req
+	}
+
+	policy := fastRetries
+	policy.RetryNonIdempotent = true
+	s.Fail("/User/Search", destiny2.CodeExternalServiceTimeout, 2)
+	c := s.Client().SetRetryPolicy(policy)
+	if _, err := c.UserService.SearchByGlobalName(context.Background(), "Guard", 0, noGetBody); err != nil {
+		t.Fatalf("SearchByGlobalName() error = %v", err)
+	}
+
+	req, _ := s.LastRequest()
+	if want := `{"displayNamePrefix":"Guard"}`; string(req.Body) != want {
+		t.Errorf("SearchByGlobalName() retried with body %q, want %q", req.Body, want)
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddGroupMembers(42, destiny2.GroupMember{})
+	s.Fail("/GroupV2", destiny2.CodeExternalServiceTimeout, 5)
+	c := s.Client().SetRetryPolicy(fastRetries)
+
+	_, err := c.GroupV2Service.GetMembersOfGroup(context.Background(), 42)
+	var apiErr *destiny2.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != destiny2.CodeExternalServiceTimeout {
+		t.Errorf("GetMembersOfGroup() error = %v, want %v", err, destiny2.CodeExternalServiceTimeout)
+	}
+	if n := s.Requests("/GroupV2/42/Members"); n != fastRetries.MaxAttempts {
+		t.Errorf("GetMembersOfGroup() made %d requests, want %d", n, fastRetries.MaxAttempts)
+	}
+}
+
+func TestRetryWaitsAsAsked(t *testing.T) {
+	tests := map[string]func(s *destiny2test.Server){
+		"ThrottleSeconds": func(s *destiny2test.Server) { s.Throttle("/GroupV2", 1, 1) },
+		"Retry-After":     func(s *destiny2test.Server) { s.Unavailable("/GroupV2", 1, 1) },
+	}
+	for name, fail := range tests {
+		s := destiny2test.NewServer()
+		s.AddGroupMembers(42, destiny2.GroupMember{})
+		fail(s)
+
+		// The policy's own delay is a millisecond so waiting a second means the server was listened to
+		start := time.Now()
+		if _, err := s.Client().SetRetryPolicy(fastRetries).GroupV2Service.GetMembersOfGroup(context.Background(), 42); err != nil {
+			t.Errorf("%s: GetMembersOfGroup() error = %v", name, err)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("%s: GetMembersOfGroup() retried after %v, want at least 1s", name, elapsed)
+		}
+		if n := s.Requests("/GroupV2/42/Members"); n != 2 {
+			t.Errorf("%s: GetMembersOfGroup() made %d requests, want 2", name, n)
+		}
+		s.Close()
+	}
+}
+
+func TestRetryCancelledDuringBackoff(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddGroupMembers(42, destiny2.GroupMember{})
+	s.Throttle("/GroupV2", 60, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := s.Client().SetRetryPolicy(fastRetries).GroupV2Service.GetMembersOfGroup(ctx, 42)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetMembersOfGroup() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetMembersOfGroup() returned after %v, want it to stop waiting when cancelled", elapsed)
+	}
+	if n := s.Requests("/GroupV2/42/Members"); n != 1 {
+		t.Errorf("GetMembersOfGroup() made %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,81 @@
+package destiny2test_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+// addSearchUsers adds users with Bungie Names to the server for players to be searched for
+func addSearchUsers(s *destiny2test.Server) {
+	s.AddUser(destiny2.UserMembershipData{
+		BungieNetUser: destiny2.GeneralUser{MembershipID: 10},
+		DestinyMemberships: []destiny2.GroupUserInfoCard{
+			{MembershipType: destiny2.MembershipTypeXbox, MembershipID: 1, BungieGlobalDisplayName: "Guard ian", BungieGlobalDisplayNameCode: 7},
+			{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 2, BungieGlobalDisplayName: "Guard ian", BungieGlobalDisplayNameCode: 7},
+		},
+	})
+	s.AddUser(destiny2.UserMembershipData{
+		BungieNetUser: destiny2.GeneralUser{MembershipID: 20},
+		DestinyMemberships: []destiny2.GroupUserInfoCard{
+			{MembershipType: destiny2.MembershipTypePSN, MembershipID: 3, BungieGlobalDisplayName: "Guard ians", BungieGlobalDisplayNameCode: 8},
+		},
+	})
+	s.AddUser(destiny2.UserMembershipData{
+		BungieNetUser: destiny2.GeneralUser{MembershipID: 30},
+		DestinyMemberships: []destiny2.GroupUserInfoCard{
+			{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 4, BungieGlobalDisplayName: "Guardian", BungieGlobalDisplayNameCode: 42},
+		},
+	})
+}
+
+func TestFindPlayer(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+	addSearchUsers(s)
+
+	c := s.Client()
+	tests := []struct {
+		query string
+		path  string
+		body  map[string]interface{}
+		cards []int64
+	}{
+		{"Guard ian", "/User/Search/GlobalName/0", map[string]interface{}{"displayNamePrefix": "Guard ian"}, []int64{1, 2, 3}},
+		{"Guardian#0042", "/Destiny2/SearchDestinyPlayerByBungieName/-1", map[string]interface{}{"displayName": "Guardian", "displayNameCode": float64(42)}, []int64{4}},
+	}
+	for _, tt := range tests {
+		cards, err := c.Destiny2Service.FindPlayer(context.Background(), tt.query)
+		if err != nil {
+			t.Errorf("FindPlayer(%q) error = %v", tt.query, err)
+			continue
+		}
+		if len(cards) != len(tt.cards) {
+			t.Errorf("FindPlayer(%q) returned %d memberships, want %d", tt.query, len(cards), len(tt.cards))
+			continue
+		}
+		for i, card := range cards {
+			if card.MembershipID != tt.cards[i] {
+				t.Errorf("FindPlayer(%q) membership %d = %d, want %d", tt.query, i, card.MembershipID, tt.cards[i])
+			}
+		}
+
+		req, _ := s.LastRequest()
+		if req.Method != http.MethodPost || req.Path != tt.path {
+			t.Errorf("FindPlayer(%q) requested %s %s, want POST %s", tt.query, req.Method, req.Path, tt.path)
+		}
+		body := map[string]interface{}{}
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			t.Errorf("FindPlayer(%q) sent an invalid body: %v", tt.query, err)
+		}
+		for k, v := range tt.body {
+			if body[k] != v {
+				t.Errorf("FindPlayer(%q) sent %s = %v, want %v", tt.query, k, body[k], v)
+			}
+		}
+	}
+}
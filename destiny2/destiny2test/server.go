@@ -0,0 +1,1058 @@
+// Package destiny2test provides an in-process fake of the Bungie API that can be used to test code
+// that uses destiny2.Client without communicating with bungie.net
+package destiny2test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/duke605/zavala/destiny2"
+	"golang.org/x/oauth2"
+)
+
+const (
+
+	// APIKey is the API key the server expects requests to be sent with
+	APIKey = "destiny2test-api-key"
+
+	// ClientID is the OAuth client ID the server expects token requests to be sent with
+	ClientID = "destiny2test-client-id"
+
+	// ClientSecret is the OAuth client secret the server expects token requests to be sent with
+	ClientSecret = "destiny2test-client-secret"
+
+	// DefaultPageSize is the number of group members returned per page when the request does not
+	// specify itemsPerPage
+	DefaultPageSize = 100
+)
+
+var (
+	profileRoute          = regexp.MustCompile(`^/Platform/Destiny2/(-?\d+)/Profile/(-?\d+)/?$`)
+	linkedProfilesRoute   = regexp.MustCompile(`^/Platform/Destiny2/(-?\d+)/Profile/(-?\d+)/LinkedProfiles/?$`)
+	membershipsByIDRoute  = regexp.MustCompile(`^/Platform/User/GetMembershipsById/(-?\d+)/(-?\d+)/?$`)
+	groupRoute            = regexp.MustCompile(`^/Platform/GroupV2/(-?\d+)/?$`)
+	groupByNameRoute      = regexp.MustCompile(`^/Platform/GroupV2/Name/([^/]+)/(\d+)/?$`)
+	activitiesRoute       = regexp.MustCompile(`^/Platform/Destiny2/(-?\d+)/Account/(-?\d+)/Character/(-?\d+)/Stats/Activities/?$`)
+	carnageReportRoute    = regexp.MustCompile(`^/Platform/Destiny2/Stats/PostGameCarnageReport/(-?\d+)/?$`)
+	statsRoute            = regexp.MustCompile(`^/Platform/Destiny2/(-?\d+)/Account/(-?\d+)/Character/(-?\d+)/Stats/?$`)
+	clanStatsRoute        = regexp.MustCompile(`^/Platform/Destiny2/Stats/AggregateClanStats/(-?\d+)/?$`)
+	bungieNameSearchRoute = regexp.MustCompile(`^/Platform/Destiny2/SearchDestinyPlayerByBungieName/(-?\d+)/?$`)
+	globalNameSearchRoute = regexp.MustCompile(`^/Platform/User/Search/GlobalName/(\d+)/?$`)
+	groupsForMemberRoute  = regexp.MustCompile(`^/Platform/GroupV2/User/(-?\d+)/(-?\d+)/(\d+)/(\d+)/?$`)
+	adminRoute            = regexp.MustCompile(`^/Platform/GroupV2/(-?\d+)/(Banned|Members/.+?)/?$`)
+	memberActionRoute     = regexp.MustCompile(`^Members/(-?\d+)/(-?\d+)/(Kick|Ban|Unban|SetMembershipType/(\d+))$`)
+	membersRoute          = regexp.MustCompile(`^/Platform/GroupV2/(-?\d+)/Members/?$`)
+)
+
+// Server is a fake Bungie API server. Responses are scripted with the methods on Server before
+// the code being tested is run
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	profiles      map[profileKey]destiny2.DestinyProfileResponse
+	linked        map[profileKey]destiny2.DestinyLinkedProfilesResponse
+	activities    map[characterKey][]destiny2.DestinyHistoricalStatsPeriodGroup
+	reports       map[int64]destiny2.DestinyPostGameCarnageReportData
+	stats         map[characterKey]map[string]destiny2.DestinyHistoricalStatsByPeriod
+	clanStats     map[int64][]destiny2.DestinyClanAggregateStat
+	users         map[int64]destiny2.UserMembershipData
+	groups        map[int64]destiny2.GroupResponse
+	members       map[int64][]destiny2.GroupMember
+	codes         map[string]destiny2.UserMembershipData
+	accessTokens  map[string]destiny2.UserMembershipData
+	refreshTokens map[string]destiny2.UserMembershipData
+	manifest      destiny2.DestinyManifest
+	content       map[string][]byte
+	failures      []*failure
+	requests      map[string]int
+	received      []Request
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+
+	// Path is the escaped path of the request without the /Platform prefix or the trailing slash
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// profileKey is the key profiles are stored under
+type profileKey struct {
+	membershipType destiny2.BungieMembershipType
+	membershipID   int64
+}
+
+// characterKey is the key character data is stored under
+type characterKey struct {
+	profileKey
+	characterID int64
+}
+
+// failure is a scripted error response
+type failure struct {
+	path       string
+	statusCode int
+	resp       destiny2.APIResponse
+	remaining  int
+
+	// retryAfter is the Retry-After header of failures sent without an error envelope, like the ones
+	// sent by Bungie's load balancers
+	retryAfter int
+}
+
+// NewServer starts and returns a new fake server. The server should be closed when it is no
+// longer needed
+func NewServer() *Server {
+	s := &Server{
+		profiles:      map[profileKey]destiny2.DestinyProfileResponse{},
+		linked:        map[profileKey]destiny2.DestinyLinkedProfilesResponse{},
+		activities:    map[characterKey][]destiny2.DestinyHistoricalStatsPeriodGroup{},
+		reports:       map[int64]destiny2.DestinyPostGameCarnageReportData{},
+		stats:         map[characterKey]map[string]destiny2.DestinyHistoricalStatsByPeriod{},
+		clanStats:     map[int64][]destiny2.DestinyClanAggregateStat{},
+		users:         map[int64]destiny2.UserMembershipData{},
+		groups:        map[int64]destiny2.GroupResponse{},
+		members:       map[int64][]destiny2.GroupMember{},
+		codes:         map[string]destiny2.UserMembershipData{},
+		accessTokens:  map[string]destiny2.UserMembershipData{},
+		refreshTokens: map[string]destiny2.UserMembershipData{},
+		content:       map[string][]byte{},
+		requests:      map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Client returns a client that communicates with the server and has the server's OAuth
+// credentials set
+func (s *Server) Client() *destiny2.Client {
+	return destiny2.NewClient(APIKey).
+		SetSiteURL(s.URL).
+		SetStatsSiteURL(s.URL).
+		SetOAuthCredentials(ClientID, ClientSecret).
+		SetHTTPClient(s.Server.Client()).
+		SetRetryPolicy(destiny2.NoRetries)
+}
+
+// AddProfile adds a profile that will be returned by GetProfile for the provided membership. The
+// whole profile is returned regardless of the components requested
+func (s *Server) AddProfile(membershipType destiny2.BungieMembershipType, membershipID int64, profile destiny2.DestinyProfileResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.profiles[profileKey{membershipType, membershipID}] = profile
+}
+
+// AddLinkedProfiles adds the linked profiles that will be returned by GetLinkedProfiles for the provided
+// membership
+func (s *Server) AddLinkedProfiles(membershipType destiny2.BungieMembershipType, membershipID int64, linked destiny2.DestinyLinkedProfilesResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.linked[profileKey{membershipType, membershipID}] = linked
+}
+
+// AddActivities adds activities to the end of the provided character's activity history, which is
+// returned most recent first
+func (s *Server) AddActivities(membershipType destiny2.BungieMembershipType, membershipID, characterID int64, activities ...destiny2.DestinyHistoricalStatsPeriodGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := characterKey{profileKey{membershipType, membershipID}, characterID}
+	s.activities[key] = append(s.activities[key], activities...)
+}
+
+// AddPostGameCarnageReport adds a report that will be returned by GetPostGameCarnageReport for the
+// activity instance in the report's details
+func (s *Server) AddPostGameCarnageReport(report destiny2.DestinyPostGameCarnageReportData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reports[report.ActivityDetails.InstanceID] = report
+}
+
+// AddHistoricalStats adds the stats that will be returned by GetHistoricalStats for the provided
+// character, keyed by mode. Use a characterID of 0 for the stats merged across the account's characters.
+// Every mode is returned regardless of the modes requested
+func (s *Server) AddHistoricalStats(membershipType destiny2.BungieMembershipType, membershipID, characterID int64, stats map[string]destiny2.DestinyHistoricalStatsByPeriod) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats[characterKey{profileKey{membershipType, membershipID}, characterID}] = stats
+}
+
+// AddClanAggregateStats adds stats that will be returned by GetClanAggregateStats for the provided group
+// when their mode is requested
+func (s *Server) AddClanAggregateStats(groupID int64, stats ...destiny2.DestinyClanAggregateStat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clanStats[groupID] = append(s.clanStats[groupID], stats...)
+}
+
+// AddGroup adds a group that will be returned by GetGroup and GetGroupByName. Groups returned by
+// GetGroupsForMember are the added groups whose roster holds the membership
+func (s *Server) AddGroup(group destiny2.GroupResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.groups[group.Detail.GroupID] = group
+}
+
+// AddGroupMembers adds members to the roster of a group
+func (s *Server) AddGroupMembers(groupID int64, members ...destiny2.GroupMember) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.members[groupID] = append(s.members[groupID], members...)
+}
+
+// SetManifest sets the version of the manifest returned by GetManifest along with the world content
+// database served for each language. The databases are zipped the way Bungie serves them and the
+// manifest pointing at them is returned
+func (s *Server) SetManifest(version string, databases map[string][]byte) destiny2.DestinyManifest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.manifest = destiny2.DestinyManifest{Version: version, MobileWorldContentPaths: map[string]string{}}
+	for language, db := range databases {
+		name := "world_sql_content_" + version + ".content"
+		path := "/common/destiny2_content/sqlite/" + language + "/" + name
+		s.manifest.MobileWorldContentPaths[language] = path
+		s.content[path] = zipFile(name, db)
+	}
+
+	return s.manifest
+}
+
+// AddUser adds a user that can authorize with the server and returns an authorization code that can
+// be exchanged for a token. Requests made with the token will act as the user
+func (s *Server) AddUser(data destiny2.UserMembershipData) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code := randomString()
+	s.codes[code] = data
+	s.addUser(data)
+
+	return code
+}
+
+// IssueToken issues a token for a user without going through the authorization flow
+func (s *Server) IssueToken(data destiny2.UserMembershipData) *oauth2.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addUser(data)
+	return s.issueToken(data)
+}
+
+// RevokeToken makes the server reject the provided token's access and refresh tokens
+func (s *Server) RevokeToken(t *oauth2.Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.accessTokens, t.AccessToken)
+	delete(s.refreshTokens, t.RefreshToken)
+}
+
+// Fail makes the next times requests to path fail with the provided error code. path is matched
+// against the start of the request's path without the /Platform prefix, so "/GroupV2" fails
+// every GroupV2 request
+func (s *Server) Fail(path string, code destiny2.PlatformErrorCode, times int) {
+	s.FailWith(path, http.StatusOK, destiny2.APIResponse{
+		ErrorCode:   code,
+		ErrorStatus: code.String(),
+		Message:     "Scripted failure",
+	}, times)
+}
+
+// Throttle makes the next times requests to path fail with a throttle error that asks the client to
+// wait for the provided number of seconds
+func (s *Server) Throttle(path string, seconds int, times int) {
+	s.FailWith(path, http.StatusOK, destiny2.APIResponse{
+		ErrorCode:       destiny2.CodeThrottleLimitExceeded,
+		ErrorStatus:     destiny2.CodeThrottleLimitExceeded.String(),
+		ThrottleSeconds: seconds,
+		Message:         "Scripted throttle",
+	}, times)
+}
+
+// Unavailable makes the next times requests to path fail with a plain 503 that asks the client to wait for the
+// provided number of seconds with the Retry-After header
+func (s *Server) Unavailable(path string, retryAfter int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &failure{
+		path:       path,
+		statusCode: http.StatusServiceUnavailable,
+		remaining:  times,
+		retryAfter: retryAfter,
+	})
+}
+
+// FailWith makes the next times requests to path fail with the provided status code and error envelope.
+// A negative times fails every request
+func (s *Server) FailWith(path string, statusCode int, resp destiny2.APIResponse, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &failure{
+		path:       path,
+		statusCode: statusCode,
+		resp:       resp,
+		remaining:  times,
+	})
+}
+
+// LastRequest returns the last request received by the server and false if the server has not
+// received any
+func (s *Server) LastRequest() (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.received) == 0 {
+		return Request{}, false
+	}
+
+	return s.received[len(s.received)-1], true
+}
+
+// Requests returns the number of requests that have been made to path, without the /Platform prefix
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[strings.TrimSuffix(path, "/")]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/Platform")
+	s.requests[strings.TrimSuffix(path, "/")]++
+
+	// Keeping the body so it can be read both by the test and the route
+	body, _ := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	s.received = append(s.received, Request{
+		Method: r.Method,
+		Path:   strings.TrimSuffix(strings.TrimPrefix(r.URL.EscapedPath(), "/Platform"), "/"),
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	// Token requests are authenticated with client credentials instead of an API key
+	if path == "/App/OAuth/token/" || path == "/App/OAuth/token" {
+		s.serveToken(w, r)
+		return
+	}
+
+	// Static content is served to anyone, like it is by Bungie's CDN
+	if strings.HasPrefix(path, "/common/") {
+		if !s.serveFailure(w, path) {
+			s.serveContent(w, r, path)
+		}
+		return
+	}
+
+	switch r.Header.Get("X-Api-Key") {
+	case APIKey:
+	case "":
+		writeError(w, http.StatusUnauthorized, destiny2.CodeApiKeyMissingFromRequest)
+		return
+	default:
+		writeError(w, http.StatusUnauthorized, destiny2.CodeApiInvalidOrExpiredKey)
+		return
+	}
+
+	// Scripted failures take priority over everything else
+	if s.serveFailure(w, path) {
+		return
+	}
+
+	if m := profileRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveProfile(w, m[1], m[2])
+		return
+	}
+
+	if m := linkedProfilesRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveLinkedProfiles(w, m[1], m[2])
+		return
+	}
+
+	if m := membershipsByIDRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveMembershipsByID(w, m[1])
+		return
+	}
+
+	if m := activitiesRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveActivities(w, r, m[1], m[2], m[3])
+		return
+	}
+
+	if m := carnageReportRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveCarnageReport(w, m[1])
+		return
+	}
+
+	if m := statsRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveHistoricalStats(w, m[1], m[2], m[3])
+		return
+	}
+
+	if m := clanStatsRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveClanAggregateStats(w, r, m[1])
+		return
+	}
+
+	if m := bungieNameSearchRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveBungieNameSearch(w, r, m[1])
+		return
+	}
+
+	if m := globalNameSearchRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveGlobalNameSearch(w, r, m[1])
+		return
+	}
+
+	if m := groupRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveGroup(w, m[1])
+		return
+	}
+
+	// The escaped path is matched so names holding a slash are kept whole
+	if m := groupByNameRoute.FindStringSubmatch(r.URL.EscapedPath()); m != nil {
+		s.serveGroupByName(w, m[1], m[2])
+		return
+	}
+
+	if m := groupsForMemberRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveGroupsForMember(w, m[1], m[2], m[4])
+		return
+	}
+
+	if m := membersRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveMembers(w, r, m[1])
+		return
+	}
+
+	if m := adminRoute.FindStringSubmatch(r.URL.Path); m != nil {
+		s.serveAdmin(w, r, m[1], m[2])
+		return
+	}
+
+	switch strings.TrimSuffix(path, "/") {
+	case "/User/GetMembershipsForCurrentUser":
+		s.serveCurrentUser(w, r)
+	case "/Destiny2/Manifest":
+		writeJSON(w, http.StatusOK, success(), s.manifest)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveFailure writes the first scripted failure for path and reports whether there was one. s.mu
+// must be held
+func (s *Server) serveFailure(w http.ResponseWriter, path string) bool {
+	for i, f := range s.failures {
+		if !strings.HasPrefix(path, f.path) || f.remaining == 0 {
+			continue
+		}
+
+		f.remaining--
+		if f.remaining == 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+
+		if f.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(f.retryAfter))
+			http.Error(w, http.StatusText(f.statusCode), f.statusCode)
+			return true
+		}
+
+		writeJSON(w, f.statusCode, f.resp, nil)
+		return true
+	}
+
+	return false
+}
+
+func (s *Server) serveContent(w http.ResponseWriter, r *http.Request, path string) {
+	b, ok := s.content[path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(b)
+}
+
+func (s *Server) serveProfile(w http.ResponseWriter, membershipType, membershipID string) {
+	mType, _ := strconv.Atoi(membershipType)
+	mID, _ := strconv.ParseInt(membershipID, 10, 64)
+
+	profile, ok := s.profiles[profileKey{destiny2.BungieMembershipType(mType), mID}]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeDestinyAccountNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), profile)
+}
+
+func (s *Server) serveLinkedProfiles(w http.ResponseWriter, membershipType, membershipID string) {
+	mType, _ := strconv.Atoi(membershipType)
+	mID, _ := strconv.ParseInt(membershipID, 10, 64)
+
+	linked, ok := s.linked[profileKey{destiny2.BungieMembershipType(mType), mID}]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeDestinyAccountNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), linked)
+}
+
+func (s *Server) serveMembershipsByID(w http.ResponseWriter, membershipID string) {
+	mID, _ := strconv.ParseInt(membershipID, 10, 64)
+
+	data, ok := s.users[mID]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeDestinyAccountNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), data)
+}
+
+func (s *Server) serveActivities(w http.ResponseWriter, r *http.Request, membershipType, membershipID, characterID string) {
+	mType, _ := strconv.Atoi(membershipType)
+	mID, _ := strconv.ParseInt(membershipID, 10, 64)
+	cID, _ := strconv.ParseInt(characterID, 10, 64)
+
+	all, ok := s.activities[characterKey{profileKey{destiny2.BungieMembershipType(mType), mID}, cID}]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeDestinyAccountNotFound)
+		return
+	}
+
+	// Activities of every mode are returned for ModeNone
+	mode, _ := strconv.Atoi(r.URL.Query().Get("mode"))
+	activities := []destiny2.DestinyHistoricalStatsPeriodGroup{}
+	for _, activity := range all {
+		if mode == int(destiny2.ModeNone) || hasMode(activity.ActivityDetails, destiny2.DestinyActivityModeType(mode)) {
+			activities = append(activities, activity)
+		}
+	}
+
+	// Pages start at 0 and an empty page is returned past the last one, the same way Bungie does
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+	if count < 1 {
+		count = destiny2.DefaultActivityHistoryPageSize
+	}
+
+	start := page * count
+	if start > len(activities) {
+		start = len(activities)
+	}
+	end := start + count
+	if end > len(activities) {
+		end = len(activities)
+	}
+
+	writeJSON(w, http.StatusOK, success(), destiny2.DestinyActivityHistoryResults{Activities: activities[start:end]})
+}
+
+func (s *Server) serveCarnageReport(w http.ResponseWriter, activityID string) {
+	id, _ := strconv.ParseInt(activityID, 10, 64)
+
+	report, ok := s.reports[id]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeDestinyPGCRNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), report)
+}
+
+func (s *Server) serveHistoricalStats(w http.ResponseWriter, membershipType, membershipID, characterID string) {
+	mType, _ := strconv.Atoi(membershipType)
+	mID, _ := strconv.ParseInt(membershipID, 10, 64)
+	cID, _ := strconv.ParseInt(characterID, 10, 64)
+
+	stats, ok := s.stats[characterKey{profileKey{destiny2.BungieMembershipType(mType), mID}, cID}]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeDestinyAccountNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), stats)
+}
+
+func (s *Server) serveClanAggregateStats(w http.ResponseWriter, r *http.Request, groupID string) {
+	gid, _ := strconv.ParseInt(groupID, 10, 64)
+
+	// Every mode is returned when none are requested
+	modes := map[destiny2.DestinyActivityModeType]bool{}
+	if q := r.URL.Query().Get("modes"); q != "" {
+		for _, mode := range strings.Split(q, ",") {
+			n, _ := strconv.Atoi(mode)
+			modes[destiny2.DestinyActivityModeType(n)] = true
+		}
+	}
+
+	stats := []destiny2.DestinyClanAggregateStat{}
+	for _, stat := range s.clanStats[gid] {
+		if len(modes) == 0 || modes[stat.Mode] {
+			stats = append(stats, stat)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, success(), stats)
+}
+
+func (s *Server) serveBungieNameSearch(w http.ResponseWriter, r *http.Request, membershipType string) {
+	mType, _ := strconv.Atoi(membershipType)
+	req := destiny2.ExactSearchRequest{}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+		writeError(w, http.StatusBadRequest, destiny2.CodeUnhandledException)
+		return
+	}
+
+	cards := []destiny2.UserInfoCard{}
+	for _, data := range s.uniqueUsers() {
+		for _, membership := range data.DestinyMemberships {
+			if mType != int(destiny2.MembershipTypeAll) && membership.MembershipType != destiny2.BungieMembershipType(mType) {
+				continue
+			}
+			if strings.EqualFold(membership.BungieGlobalDisplayName, req.DisplayName) && membership.BungieGlobalDisplayNameCode == req.DisplayNameCode {
+				cards = append(cards, membership.UserInfoCard())
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, success(), cards)
+}
+
+func (s *Server) serveGlobalNameSearch(w http.ResponseWriter, r *http.Request, page string) {
+	req := destiny2.UserSearchPrefixRequest{}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+		writeError(w, http.StatusBadRequest, destiny2.CodeUnhandledException)
+		return
+	}
+
+	// Every match is returned on the first page
+	result := destiny2.UserSearchResponse{SearchResults: []destiny2.UserSearchResponseDetail{}}
+	result.Page, _ = strconv.Atoi(page)
+	for _, data := range s.uniqueUsers() {
+		if result.Page > 0 {
+			break
+		}
+		if len(data.DestinyMemberships) == 0 {
+			continue
+		}
+
+		card := data.DestinyMemberships[0]
+		if !strings.HasPrefix(strings.ToLower(card.BungieGlobalDisplayName), strings.ToLower(req.DisplayNamePrefix)) {
+			continue
+		}
+
+		detail := destiny2.UserSearchResponseDetail{
+			BungieGlobalDisplayName:     card.BungieGlobalDisplayName,
+			BungieGlobalDisplayNameCode: card.BungieGlobalDisplayNameCode,
+			BungieNetMembershipID:       data.BungieNetUser.MembershipID,
+		}
+		for _, membership := range data.DestinyMemberships {
+			detail.DestinyMemberships = append(detail.DestinyMemberships, membership.UserInfoCard())
+		}
+		result.SearchResults = append(result.SearchResults, detail)
+	}
+
+	writeJSON(w, http.StatusOK, success(), result)
+}
+
+func (s *Server) serveGroup(w http.ResponseWriter, groupID string) {
+	gid, _ := strconv.ParseInt(groupID, 10, 64)
+
+	group, ok := s.groups[gid]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeGroupNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), group)
+}
+
+func (s *Server) serveGroupByName(w http.ResponseWriter, escapedName, groupType string) {
+	name, _ := url.PathUnescape(escapedName)
+	gType, _ := strconv.Atoi(groupType)
+
+	// Bungie ignores the case of the name
+	for _, group := range s.groups {
+		if strings.EqualFold(group.Detail.Name, name) && group.Detail.GroupType == destiny2.GroupType(gType) {
+			writeJSON(w, http.StatusOK, success(), group)
+			return
+		}
+	}
+
+	writeError(w, http.StatusOK, destiny2.CodeGroupNotFound)
+}
+
+func (s *Server) serveGroupsForMember(w http.ResponseWriter, membershipType, membershipID, groupType string) {
+	mType, _ := strconv.Atoi(membershipType)
+	mID, _ := strconv.ParseInt(membershipID, 10, 64)
+	gType, _ := strconv.Atoi(groupType)
+
+	result := destiny2.GetGroupsForMemberResponse{Results: []destiny2.GroupMembership{}}
+	for gid, members := range s.members {
+		group, ok := s.groups[gid]
+		if !ok || group.Detail.GroupType != destiny2.GroupType(gType) {
+			continue
+		}
+
+		for _, member := range members {
+			info := member.DestinyUserInfo
+			if info.MembershipType == destiny2.BungieMembershipType(mType) && info.MembershipID == mID {
+				result.Results = append(result.Results, destiny2.GroupMembership{Member: member, Group: group.Detail})
+			}
+		}
+	}
+	result.TotalResults = len(result.Results)
+
+	writeJSON(w, http.StatusOK, success(), result)
+}
+
+func (s *Server) serveMembers(w http.ResponseWriter, r *http.Request, groupID string) {
+	gid, _ := strconv.ParseInt(groupID, 10, 64)
+
+	members, ok := s.members[gid]
+	if !ok {
+		writeError(w, http.StatusOK, destiny2.CodeGroupNotFound)
+		return
+	}
+
+	// Paging through the members the same way Bungie does
+	page, _ := strconv.Atoi(r.URL.Query().Get("currentPage"))
+	if page < 1 {
+		page = 1
+	}
+	size, _ := strconv.Atoi(r.URL.Query().Get("itemsPerPage"))
+	if size < 1 {
+		size = DefaultPageSize
+	}
+
+	start := (page - 1) * size
+	if start > len(members) {
+		start = len(members)
+	}
+	end := start + size
+	if end > len(members) {
+		end = len(members)
+	}
+
+	result := destiny2.SearchResultOfGroupMember{Results: members[start:end]}
+	result.TotalResults = len(members)
+	result.HasMore = end < len(members)
+	result.Query = destiny2.PagedQuery{ItemsPerPage: size, CurrentPage: page}
+
+	writeJSON(w, http.StatusOK, success(), result)
+}
+
+// serveAdmin serves the group admin endpoints. Requests must be authorized by a user with a Destiny
+// membership that is an admin of the group
+func (s *Server) serveAdmin(w http.ResponseWriter, r *http.Request, groupID, action string) {
+	gid, _ := strconv.ParseInt(groupID, 10, 64)
+
+	data, ok := s.accessTokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, destiny2.CodeWebAuthRequired)
+		return
+	}
+	if !s.isAdmin(gid, data) {
+		writeError(w, http.StatusOK, destiny2.CodeInsufficientPrivileges)
+		return
+	}
+
+	// Lists are read with GET and everything else changes the group with POST
+	method := http.MethodPost
+	if action == "Banned" || action == "Members/Pending" || action == "Members/InvitedIndividuals" {
+		method = http.MethodGet
+	}
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, destiny2.CodeBadRequest)
+		return
+	}
+
+	switch {
+	case action == "Banned":
+		writeJSON(w, http.StatusOK, success(), destiny2.SearchResultOfGroupBan{Results: []destiny2.GroupBan{}})
+	case action == "Members/Pending" || action == "Members/InvitedIndividuals":
+		writeJSON(w, http.StatusOK, success(), destiny2.SearchResultOfGroupMemberApplication{Results: []destiny2.GroupMemberApplication{}})
+	case strings.HasPrefix(action, "Members/Approve/"):
+		writeJSON(w, http.StatusOK, success(), true)
+	case action == "Members/ApproveList" || action == "Members/DenyList":
+		req := destiny2.GroupApplicationListRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, destiny2.CodeInvalidPostBody)
+			return
+		}
+
+		results := []destiny2.EntityActionResult{}
+		for _, membership := range req.Memberships {
+			results = append(results, destiny2.EntityActionResult{EntityID: membership.MembershipID, Result: destiny2.CodeSuccess})
+		}
+		writeJSON(w, http.StatusOK, success(), results)
+	case action == "Members/ApproveAll" || action == "Members/DenyAll":
+		writeJSON(w, http.StatusOK, success(), []destiny2.EntityActionResult{})
+	case strings.HasPrefix(action, "Members/IndividualInvite/") || strings.HasPrefix(action, "Members/IndividualInviteCancel/"):
+		writeJSON(w, http.StatusOK, success(), destiny2.GroupApplicationResponse{})
+	default:
+		m := memberActionRoute.FindStringSubmatch(action)
+		if m == nil {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveMemberAction(w, gid, m[1], m[2], m[3], m[4])
+	}
+}
+
+// serveMemberAction serves the admin endpoints that act on a single member of a group. Kicks and member
+// type changes are applied to the group's roster
+func (s *Server) serveMemberAction(w http.ResponseWriter, gid int64, membershipType, membershipID, action, memberType string) {
+	mType, _ := strconv.Atoi(membershipType)
+	mID, _ := strconv.ParseInt(membershipID, 10, 64)
+
+	members := s.members[gid]
+	for i := 0; i < len(members); i++ {
+		info := members[i].DestinyUserInfo
+		if info.MembershipType != destiny2.BungieMembershipType(mType) || info.MembershipID != mID {
+			continue
+		}
+
+		switch {
+		case action == "Kick":
+			members = append(members[:i], members[i+1:]...)
+			i--
+		case strings.HasPrefix(action, "SetMembershipType/"):
+			t, _ := strconv.Atoi(memberType)
+			members[i].MemberType = destiny2.GroupMemberType(t)
+		}
+	}
+	s.members[gid] = members
+
+	if action == "Kick" {
+		writeJSON(w, http.StatusOK, success(), destiny2.GroupMemberLeaveResult{Group: s.groups[gid].Detail})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), 0)
+}
+
+// isAdmin reports whether one of the user's Destiny memberships is an admin of the group. s.mu must be held
+func (s *Server) isAdmin(gid int64, data destiny2.UserMembershipData) bool {
+	for _, member := range s.members[gid] {
+		if member.MemberType < destiny2.MemberTypeAdmin {
+			continue
+		}
+
+		for _, membership := range data.DestinyMemberships {
+			if membership.MembershipType == member.DestinyUserInfo.MembershipType && membership.MembershipID == member.DestinyUserInfo.MembershipID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *Server) serveCurrentUser(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.accessTokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, destiny2.CodeWebAuthRequired)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, success(), data)
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Client credentials can either be sent with basic auth or in the body
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != ClientID || secret != ClientSecret {
+		writeOAuthError(w, "invalid_client")
+		return
+	}
+
+	var data destiny2.UserMembershipData
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		code := r.PostForm.Get("code")
+		if data, ok = s.codes[code]; !ok {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		delete(s.codes, code)
+	case "refresh_token":
+		// Refresh tokens can only be used once, just like Bungie's
+		refresh := r.PostForm.Get("refresh_token")
+		if data, ok = s.refreshTokens[refresh]; !ok {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		delete(s.refreshTokens, refresh)
+	default:
+		writeOAuthError(w, "unsupported_grant_type")
+		return
+	}
+
+	t := s.issueToken(data)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token":       t.AccessToken,
+		"token_type":         "Bearer",
+		"expires_in":         3600,
+		"refresh_token":      t.RefreshToken,
+		"refresh_expires_in": 7776000,
+		"membership_id":      strconv.FormatInt(data.BungieNetUser.MembershipID, 10),
+	})
+}
+
+// addUser makes the user's membership data available by its Bungie.net and Destiny membership IDs.
+// s.mu must be held
+func (s *Server) addUser(data destiny2.UserMembershipData) {
+	s.users[data.BungieNetUser.MembershipID] = data
+	for _, membership := range data.DestinyMemberships {
+		s.users[membership.MembershipID] = data
+	}
+}
+
+// hasMode reports whether the activity is of the provided mode
+func hasMode(activity destiny2.DestinyHistoricalStatsActivity, mode destiny2.DestinyActivityModeType) bool {
+	if activity.Mode == mode {
+		return true
+	}
+	for _, m := range activity.Modes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uniqueUsers returns every user added to the server once, ordered by Bungie.net membership ID. s.mu
+// must be held
+func (s *Server) uniqueUsers() []destiny2.UserMembershipData {
+	users := map[int64]destiny2.UserMembershipData{}
+	for _, data := range s.users {
+		users[data.BungieNetUser.MembershipID] = data
+	}
+
+	ids := make([]int64, 0, len(users))
+	for id := range users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	unique := make([]destiny2.UserMembershipData, len(ids))
+	for i, id := range ids {
+		unique[i] = users[id]
+	}
+
+	return unique
+}
+
+// issueToken issues a new access and refresh token for the user. s.mu must be held
+func (s *Server) issueToken(data destiny2.UserMembershipData) *oauth2.Token {
+	t := &oauth2.Token{
+		AccessToken:  randomString(),
+		RefreshToken: randomString(),
+		TokenType:    "Bearer",
+	}
+	s.accessTokens[t.AccessToken] = data
+	s.refreshTokens[t.RefreshToken] = data
+
+	return t
+}
+
+// success returns the envelope of a successful response
+func success() destiny2.APIResponse {
+	return destiny2.APIResponse{
+		ErrorCode:   destiny2.CodeSuccess,
+		ErrorStatus: destiny2.CodeSuccess.String(),
+		Message:     "Ok",
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code destiny2.PlatformErrorCode) {
+	writeJSON(w, statusCode, destiny2.APIResponse{
+		ErrorCode:   code,
+		ErrorStatus: code.String(),
+		Message:     code.String(),
+	}, nil)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, envelope destiny2.APIResponse, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(struct {
+		Response interface{} `json:"Response,omitempty"`
+		destiny2.APIResponse
+	}{resp, envelope})
+}
+
+func writeOAuthError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// zipFile returns an archive holding a single file with the provided name and contents
+func zipFile(name string, b []byte) []byte {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	// Writes to a buffer cannot fail
+	f, _ := zw.Create(name)
+	f.Write(b)
+	zw.Close()
+
+	return buf.Bytes()
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,258 @@
+package destiny2test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+func TestGetProfile(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddProfile(destiny2.MembershipTypeSteam, 1234, destiny2.DestinyProfileResponse{
+		Profile: &destiny2.SingleComponentResponseOfDestinyProfileComponent{
+			Data: destiny2.DestinyProfileComponent{CharacterIds: destiny2.Int64Slice{1, 2, 3}},
+		},
+	})
+
+	profile, err := s.Client().Destiny2Service.GetProfile(context.Background(), destiny2.MembershipTypeSteam, 1234, destiny2.Profiles)
+	if err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+	if got := len(profile.Profile.Data.CharacterIds); got != 3 {
+		t.Errorf("GetProfile() returned %d characters, want 3", got)
+	}
+}
+
+func TestGetProfileNotFound(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	_, err := s.Client().Destiny2Service.GetProfile(context.Background(), destiny2.MembershipTypeSteam, 1234, destiny2.Profiles)
+
+	var apiErr *destiny2.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetProfile() error = %v, want *APIError", err)
+	}
+	if apiErr.ErrorCode != destiny2.CodeDestinyAccountNotFound {
+		t.Errorf("GetProfile() error code = %v, want %v", apiErr.ErrorCode, destiny2.CodeDestinyAccountNotFound)
+	}
+}
+
+func TestInvalidAPIKey(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	c := destiny2.NewClient("wrong").SetSiteURL(s.URL).SetRetryPolicy(destiny2.NoRetries)
+	_, err := c.GroupV2Service.GetMembersOfGroup(context.Background(), 1)
+	if !errors.Is(err, destiny2.ErrUnautorized) {
+		t.Errorf("GetMembersOfGroup() error = %v, want %v", err, destiny2.ErrUnautorized)
+	}
+}
+
+func TestGetAllMembersOfGroup(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	members := make([]destiny2.GroupMember, 250)
+	for i := range members {
+		members[i].DestinyUserInfo.MembershipID = int64(i)
+	}
+	s.AddGroupMembers(42, members...)
+
+	got, err := s.Client().GroupV2Service.GetAllMembersOfGroup(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetAllMembersOfGroup() error = %v", err)
+	}
+	if len(got) != len(members) {
+		t.Errorf("GetAllMembersOfGroup() returned %d members, want %d", len(got), len(members))
+	}
+	if n := s.Requests("/GroupV2/42/Members"); n != 3 {
+		t.Errorf("GetAllMembersOfGroup() made %d requests, want 3", n)
+	}
+}
+
+func TestThrottleRetried(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddGroupMembers(42, destiny2.GroupMember{})
+	s.Throttle("/GroupV2", 0, 2)
+
+	c := s.Client().SetRetryPolicy(destiny2.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	if _, err := c.GroupV2Service.GetMembersOfGroup(context.Background(), 42); err != nil {
+		t.Fatalf("GetMembersOfGroup() error = %v", err)
+	}
+	if n := s.Requests("/GroupV2/42/Members"); n != 3 {
+		t.Errorf("GetMembersOfGroup() made %d requests, want 3", n)
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.Fail("/GroupV2", destiny2.CodeSystemDisabled, 1)
+
+	_, err := s.Client().GroupV2Service.GetMembersOfGroup(context.Background(), 42)
+	if !destiny2.IsMaintenance(err) {
+		t.Errorf("GetMembersOfGroup() error = %v, want maintenance", err)
+	}
+}
+
+func TestOAuthFlow(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	user := destiny2.UserMembershipData{BungieNetUser: destiny2.GeneralUser{MembershipID: 99, DisplayName: "Guardian"}}
+	code := s.AddUser(user)
+
+	ctx := context.Background()
+	c := s.Client()
+	token, err := c.Exchange(ctx, code)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	data, err := c.WithOAuth2Token(ctx, token).UserService.GetMembershipDataForCurrentUser(ctx)
+	if err != nil {
+		t.Fatalf("GetMembershipDataForCurrentUser() error = %v", err)
+	}
+	if data.BungieNetUser.DisplayName != "Guardian" {
+		t.Errorf("GetMembershipDataForCurrentUser() display name = %q, want %q", data.BungieNetUser.DisplayName, "Guardian")
+	}
+
+	s.RevokeToken(token)
+	_, err = c.WithOAuth2Token(ctx, token).UserService.GetMembershipDataForCurrentUser(ctx)
+	if !errors.Is(err, destiny2.ErrWebAuthRequired) {
+		t.Errorf("GetMembershipDataForCurrentUser() with revoked token error = %v, want %v", err, destiny2.ErrWebAuthRequired)
+	}
+}
+
+func TestMiddlewareWithoutResponse(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	c := s.Client().Use(func(next destiny2.Doer) destiny2.Doer {
+		return destiny2.DoerFunc(func(req *http.Request) (*destiny2.Response, error) {
+			return nil, nil
+		})
+	})
+	_, err := c.GroupV2Service.GetMembersOfGroup(context.Background(), 42)
+	if !errors.Is(err, destiny2.ErrNoResponse) {
+		t.Errorf("GetMembersOfGroup() error = %v, want %v", err, destiny2.ErrNoResponse)
+	}
+}
+
+func TestGetGroupByName(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddGroup(destiny2.GroupResponse{Detail: destiny2.GroupV2{GroupID: 42, Name: "Iron/Banner Clan", GroupType: destiny2.GroupTypeClan}})
+
+	ctx := context.Background()
+	c := s.Client()
+	group, err := c.GroupV2Service.GetGroupByName(ctx, "iron/banner clan", destiny2.GroupTypeClan)
+	if err != nil {
+		t.Fatalf("GetGroupByName() error = %v", err)
+	}
+	if group.Detail.GroupID != 42 {
+		t.Errorf("GetGroupByName() returned group %d, want 42", group.Detail.GroupID)
+	}
+
+	_, err = c.GroupV2Service.GetGroupByName(ctx, "Iron/Banner Clan", destiny2.GroupTypeGeneral)
+	var apiErr *destiny2.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != destiny2.CodeGroupNotFound {
+		t.Errorf("GetGroupByName() error = %v, want %v", err, destiny2.CodeGroupNotFound)
+	}
+
+	if group, err = c.GroupV2Service.GetGroup(ctx, 42); err != nil || group.Detail.Name != "Iron/Banner Clan" {
+		t.Errorf("GetGroup() = %q, %v, want %q, nil", group.Detail.Name, err, "Iron/Banner Clan")
+	}
+}
+
+func TestIsMemberOfGroup(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	member := destiny2.GroupMember{GroupID: 42}
+	member.DestinyUserInfo.MembershipType = destiny2.MembershipTypeSteam
+	member.DestinyUserInfo.MembershipID = 1234
+	s.AddGroup(destiny2.GroupResponse{Detail: destiny2.GroupV2{GroupID: 42, GroupType: destiny2.GroupTypeClan}})
+	s.AddGroupMembers(42, member)
+
+	tests := []struct {
+		membershipID, gid int64
+		want              bool
+	}{
+		{1234, 42, true},
+		{1234, 43, false},
+		{5678, 42, false},
+	}
+	for _, tt := range tests {
+		got, err := s.Client().GroupV2Service.IsMemberOfGroup(context.Background(), destiny2.MembershipTypeSteam, tt.membershipID, tt.gid)
+		if err != nil {
+			t.Fatalf("IsMemberOfGroup() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("IsMemberOfGroup(%d, %d) = %t, want %t", tt.membershipID, tt.gid, got, tt.want)
+		}
+	}
+}
+
+func TestGetLinkedProfilesRoute(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddLinkedProfiles(destiny2.MembershipTypeSteam, 1234, destiny2.DestinyLinkedProfilesResponse{
+		Profiles: []destiny2.DestinyProfileUserInfoCard{{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 1234}},
+	})
+
+	linked, err := s.Client().Destiny2Service.GetLinkedProfiles(context.Background(), destiny2.MembershipTypeSteam, 1234)
+	if err != nil {
+		t.Fatalf("GetLinkedProfiles() error = %v", err)
+	}
+	if len(linked.Profiles) != 1 || linked.Profiles[0].MembershipID != 1234 {
+		t.Errorf("GetLinkedProfiles() profiles = %+v, want membership 1234", linked.Profiles)
+	}
+
+	req, _ := s.LastRequest()
+	if want := "/Destiny2/3/Profile/1234/LinkedProfiles"; req.Method != "GET" || req.Path != want {
+		t.Errorf("GetLinkedProfiles() requested %s %s, want GET %s", req.Method, req.Path, want)
+	}
+}
+
+func TestGetMembershipDataByIDRoute(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddUser(destiny2.UserMembershipData{
+		BungieNetUser:      destiny2.GeneralUser{MembershipID: 99},
+		DestinyMemberships: []destiny2.GroupUserInfoCard{{MembershipType: destiny2.MembershipTypeSteam, MembershipID: 1234}},
+	})
+
+	// Users can be looked up by either their Bungie.net or Destiny membership
+	c := s.Client()
+	for _, id := range []int64{99, 1234} {
+		data, err := c.UserService.GetMembershipDataByID(context.Background(), id, destiny2.MembershipTypeAll)
+		if err != nil {
+			t.Fatalf("GetMembershipDataByID(%d) error = %v", id, err)
+		}
+		if data.BungieNetUser.MembershipID != 99 {
+			t.Errorf("GetMembershipDataByID(%d) returned user %d, want 99", id, data.BungieNetUser.MembershipID)
+		}
+
+		// The membership ID comes before the type, unlike most endpoints
+		req, _ := s.LastRequest()
+		if want := fmt.Sprintf("/User/GetMembershipsById/%d/-1", id); req.Method != "GET" || req.Path != want {
+			t.Errorf("GetMembershipDataByID(%d) requested %s %s, want GET %s", id, req.Method, req.Path, want)
+		}
+	}
+}
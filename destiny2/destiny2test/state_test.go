@@ -0,0 +1,187 @@
+package destiny2test_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+)
+
+var stateKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newStateManager(t *testing.T, ttl time.Duration) *destiny2.StateManager {
+	m, err := destiny2.NewStateManager(stateKey, ttl)
+	if err != nil {
+		t.Fatalf("NewStateManager() error = %v", err)
+	}
+
+	return m
+}
+
+func TestNewStateManagerShortKey(t *testing.T) {
+	_, err := destiny2.NewStateManager(stateKey[:destiny2.MinStateKeyLength-1], time.Minute)
+	if err != destiny2.ErrShortStateKey {
+		t.Errorf("NewStateManager() error = %v, want %v", err, destiny2.ErrShortStateKey)
+	}
+}
+
+func TestStateVerify(t *testing.T) {
+	m := newStateManager(t, time.Minute)
+	state, err := m.Issue("user", "guild")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	s, err := m.Verify(state)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if s.UserID != "user" || s.GuildID != "guild" {
+		t.Errorf("Verify() = %+v, want user %q and guild %q", s, "user", "guild")
+	}
+
+	if _, err = m.Verify(state); err != destiny2.ErrStateReused {
+		t.Errorf("Verify() reused state error = %v, want %v", err, destiny2.ErrStateReused)
+	}
+}
+
+func TestStateTampered(t *testing.T) {
+	m := newStateManager(t, time.Minute)
+	state, err := m.Issue("user", "guild")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	// Swapping the payload for one naming another user while keeping the signature
+	parts := strings.Split(state, ".")
+	b, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	forged := strings.Replace(string(b), `"u":"user"`, `"u":"admin"`, 1)
+	forgedState := base64.RawURLEncoding.EncodeToString([]byte(forged)) + "." + parts[1]
+
+	other, err := destiny2.NewStateManager([]byte(strings.Repeat("k", destiny2.MinStateKeyLength)), time.Minute)
+	if err != nil {
+		t.Fatalf("NewStateManager() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		m     *destiny2.StateManager
+		state string
+	}{
+		"forged payload":  {m, forgedState},
+		"no signature":    {m, parts[0]},
+		"bad signature":   {m, parts[0] + ".c2lnbmF0dXJl"},
+		"other key":       {other, state},
+		"not a state":     {m, "garbage"},
+		"empty state":     {m, ""},
+		"extra separator": {m, state + ".extra"},
+	}
+	for name, tt := range tests {
+		if _, err := tt.m.Verify(tt.state); err != destiny2.ErrInvalidState {
+			t.Errorf("%s: Verify() error = %v, want %v", name, err, destiny2.ErrInvalidState)
+		}
+	}
+}
+
+func TestStateExpired(t *testing.T) {
+	m := newStateManager(t, -time.Minute)
+	state, err := m.Issue("user", "guild")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	if _, err = m.Verify(state); err != destiny2.ErrStateExpired {
+		t.Errorf("Verify() error = %v, want %v", err, destiny2.ErrStateExpired)
+	}
+}
+
+// usedNonces is a NonceStore shared by several state managers like a database would be
+type usedNonces map[string]bool
+
+func (u usedNonces) Use(nonce string, expires time.Time) (bool, error) {
+	if u[nonce] {
+		return false, nil
+	}
+	u[nonce] = true
+
+	return true, nil
+}
+
+func TestStateNonceStore(t *testing.T) {
+	nonces := usedNonces{}
+	first := newStateManager(t, time.Minute).SetNonceStore(nonces)
+	second := newStateManager(t, time.Minute).SetNonceStore(nonces)
+
+	state, err := first.Issue("user", "guild")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if _, err = first.Verify(state); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if _, err = second.Verify(state); err != destiny2.ErrStateReused {
+		t.Errorf("Verify() on another manager error = %v, want %v", err, destiny2.ErrStateReused)
+	}
+}
+
+func TestStatePKCE(t *testing.T) {
+	var challenge string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer api.Close()
+
+	c := destiny2.NewClient("key").SetSiteURL(api.URL)
+	c.SetOAuthCredentials("client", "secret")
+
+	m := newStateManager(t, time.Minute)
+	authURL, err := m.AuthURL(c, "user", "guild")
+	if err != nil {
+		t.Fatalf("AuthURL() error = %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("AuthURL() returned an invalid URL: %v", err)
+	}
+	q := u.Query()
+	if method := q.Get("code_challenge_method"); method != "S256" {
+		t.Errorf("AuthURL() code_challenge_method = %q, want S256", method)
+	}
+	challenge = q.Get("code_challenge")
+
+	s, token, err := m.Exchange(context.Background(), c, q.Get("state"), "code")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if s.UserID != "user" {
+		t.Errorf("Exchange() user = %q, want %q", s.UserID, "user")
+	}
+	if token.AccessToken != "access" {
+		t.Errorf("Exchange() access token = %q, want %q", token.AccessToken, "access")
+	}
+}
@@ -0,0 +1,95 @@
+package destiny2test_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+)
+
+func TestGetHistoricalStatsAllCharacters(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddHistoricalStats(destiny2.MembershipTypeSteam, 1, 0, map[string]destiny2.DestinyHistoricalStatsByPeriod{
+		"raid": {AllTime: destiny2.DestinyHistoricalStatsValues{
+			"kills": {Basic: destiny2.DestinyHistoricalStatsValuePair{Value: 120}, Pga: &destiny2.DestinyHistoricalStatsValuePair{Value: 12}},
+		}},
+	})
+
+	modes := []destiny2.DestinyActivityModeType{destiny2.ModeRaid, destiny2.ModeAllPvP}
+	groups := []destiny2.DestinyStatsGroupType{destiny2.StatsGroupGeneral, destiny2.StatsGroupWeapons}
+	stats, err := s.Client().Destiny2Service.GetHistoricalStats(context.Background(), destiny2.MembershipTypeSteam, 1, 0, destiny2.PeriodTypeAllTime, modes, groups)
+	if err != nil {
+		t.Fatalf("GetHistoricalStats() error = %v", err)
+	}
+
+	req, _ := s.LastRequest()
+	if want := "/Destiny2/3/Account/1/Character/0/Stats"; req.Path != want {
+		t.Errorf("GetHistoricalStats() requested %s, want %s", req.Path, want)
+	}
+	want := url.Values{"periodType": {"2"}, "modes": {"4,5"}, "groups": {"1,2"}}
+	if req.Query.Encode() != want.Encode() {
+		t.Errorf("GetHistoricalStats() query = %s, want %s", req.Query.Encode(), want.Encode())
+	}
+
+	kills := stats["raid"].AllTime
+	if kills.Basic("kills") != 120 || kills.PerGame("kills") != 12 {
+		t.Errorf("GetHistoricalStats() kills = %v, %v per game, want 120, 12 per game", kills.Basic("kills"), kills.PerGame("kills"))
+	}
+}
+
+func TestOptionDayRange(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddHistoricalStats(destiny2.MembershipTypeSteam, 1, 2, map[string]destiny2.DestinyHistoricalStatsByPeriod{})
+
+	start := time.Date(2021, time.March, 1, 23, 0, 0, 0, time.UTC)
+	end := time.Date(2021, time.March, 7, 1, 0, 0, 0, time.UTC)
+	_, err := s.Client().Destiny2Service.GetHistoricalStats(context.Background(), destiny2.MembershipTypeSteam, 1, 2, destiny2.PeriodTypeDaily, nil, nil,
+		destiny2.OptionDayRange(start, end),
+		destiny2.OptionDayRange(start, end.AddDate(0, 0, 1)),
+	)
+	if err != nil {
+		t.Fatalf("GetHistoricalStats() error = %v", err)
+	}
+
+	// Days are replaced rather than added to when the option is used more than once
+	req, _ := s.LastRequest()
+	want := url.Values{"periodType": {"1"}, "daystart": {"2021-03-01"}, "dayend": {"2021-03-08"}}
+	if req.Query.Encode() != want.Encode() {
+		t.Errorf("GetHistoricalStats() query = %s, want %s", req.Query.Encode(), want.Encode())
+	}
+}
+
+func TestGetClanAggregateStats(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	s.AddClanAggregateStats(42,
+		destiny2.DestinyClanAggregateStat{Mode: destiny2.ModeRaid, StatID: "lbActivitiesEntered"},
+		destiny2.DestinyClanAggregateStat{Mode: destiny2.ModeAllPvP, StatID: "lbKills"},
+		destiny2.DestinyClanAggregateStat{Mode: destiny2.ModeGambit, StatID: "lbKills"},
+	)
+
+	modes := []destiny2.DestinyActivityModeType{destiny2.ModeRaid, destiny2.ModeGambit}
+	stats, err := s.Client().Destiny2Service.GetClanAggregateStats(context.Background(), 42, modes)
+	if err != nil {
+		t.Fatalf("GetClanAggregateStats() error = %v", err)
+	}
+	if len(stats) != 2 || stats[0].Mode != destiny2.ModeRaid || stats[1].Mode != destiny2.ModeGambit {
+		t.Errorf("GetClanAggregateStats() = %+v, want raid and gambit stats", stats)
+	}
+
+	req, _ := s.LastRequest()
+	if want := "/Destiny2/Stats/AggregateClanStats/42"; req.Path != want {
+		t.Errorf("GetClanAggregateStats() requested %s, want %s", req.Path, want)
+	}
+	if got := req.Query.Get("modes"); got != "4,63" {
+		t.Errorf("GetClanAggregateStats() modes = %s, want 4,63", got)
+	}
+}
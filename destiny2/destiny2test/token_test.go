@@ -0,0 +1,230 @@
+package destiny2test_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duke605/zavala/destiny2"
+	"github.com/duke605/zavala/destiny2/destiny2test"
+	"golang.org/x/oauth2"
+)
+
+// memoryTokenStore is a TokenStore that keeps tokens in memory and counts how many times they are saved
+type memoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[int64]*oauth2.Token
+	saves  int
+}
+
+func newMemoryTokenStore(membershipID int64, t *oauth2.Token) *memoryTokenStore {
+	return &memoryTokenStore{tokens: map[int64]*oauth2.Token{membershipID: t}}
+}
+
+func (s *memoryTokenStore) LoadToken(ctx context.Context, membershipID int64) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens[membershipID], nil
+}
+
+func (s *memoryTokenStore) SaveToken(ctx context.Context, membershipID int64, t *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[membershipID] = t
+	s.saves++
+	return nil
+}
+
+// blockingTokenStore is a LockingTokenStore that holds the lock until it is released
+type blockingTokenStore struct {
+	*memoryTokenStore
+	locked  chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingTokenStore) WithTokenLock(ctx context.Context, membershipID int64, fn func(*oauth2.Token) (*oauth2.Token, error)) (*oauth2.Token, error) {
+	close(s.locked)
+	<-s.release
+
+	current, _ := s.LoadToken(ctx, membershipID)
+	t, err := fn(current)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, s.SaveToken(ctx, membershipID, t)
+}
+
+// expiredToken issues a token for the user that the client considers expired
+func expiredToken(s *destiny2test.Server, user destiny2.UserMembershipData) *oauth2.Token {
+	t := s.IssueToken(user)
+	t.Expiry = time.Now().Add(-time.Minute)
+	return t
+}
+
+func TestConcurrentRefreshes(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	user := destiny2.UserMembershipData{BungieNetUser: destiny2.GeneralUser{MembershipID: 99}}
+	store := newMemoryTokenStore(99, expiredToken(s, user))
+
+	ctx := context.Background()
+	c := s.Client()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			userClient, err := c.ForUser(ctx, store, 99)
+			if err != nil {
+				t.Errorf("ForUser() error = %v", err)
+				return
+			}
+			if _, err = userClient.UserService.GetMembershipDataForCurrentUser(ctx); err != nil {
+				t.Errorf("GetMembershipDataForCurrentUser() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Refresh tokens can only be used once so a second refresh would have failed
+	if n := s.Requests("/App/OAuth/token"); n != 1 {
+		t.Errorf("token was refreshed %d times, want 1", n)
+	}
+	if store.saves != 1 {
+		t.Errorf("token was saved %d times, want 1", store.saves)
+	}
+}
+
+func TestRefreshWaitHonorsContext(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	user := destiny2.UserMembershipData{BungieNetUser: destiny2.GeneralUser{MembershipID: 99}}
+	store := &blockingTokenStore{
+		memoryTokenStore: newMemoryTokenStore(99, expiredToken(s, user)),
+		locked:           make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+
+	// The first client's refresh holds the lock until the test is done
+	c := s.Client()
+	first, err := c.ForUser(context.Background(), store, 99)
+	if err != nil {
+		t.Fatalf("ForUser() error = %v", err)
+	}
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := first.UserService.GetMembershipDataForCurrentUser(context.Background())
+		firstDone <- err
+	}()
+	<-store.locked
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	second, err := c.ForUser(ctx, store, 99)
+	if err != nil {
+		t.Fatalf("ForUser() error = %v", err)
+	}
+
+	secondDone := make(chan error, 1)
+	go func() {
+		_, err := second.UserService.GetMembershipDataForCurrentUser(context.Background())
+		secondDone <- err
+	}()
+	select {
+	case err := <-secondDone:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("GetMembershipDataForCurrentUser() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("waiting on another refresh ignored the cancelled context")
+	}
+
+	close(store.release)
+	if err := <-firstDone; err != nil {
+		t.Errorf("GetMembershipDataForCurrentUser() error = %v", err)
+	}
+}
+
+// nilTokenStore is a TokenStore that has no tokens
+type nilTokenStore struct{}
+
+func (nilTokenStore) LoadToken(ctx context.Context, membershipID int64) (*oauth2.Token, error) {
+	return nil, nil
+}
+
+func (nilTokenStore) SaveToken(ctx context.Context, membershipID int64, t *oauth2.Token) error {
+	return nil
+}
+
+func TestForUserWithoutToken(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	if _, err := s.Client().ForUser(context.Background(), nilTokenStore{}, 99); err != destiny2.ErrNoToken {
+		t.Errorf("ForUser() error = %v, want %v", err, destiny2.ErrNoToken)
+	}
+}
+
+func TestForUserRefreshesExpiredToken(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	user := destiny2.UserMembershipData{BungieNetUser: destiny2.GeneralUser{MembershipID: 99}}
+	expired := expiredToken(s, user)
+	store := newMemoryTokenStore(99, expired)
+
+	ctx := context.Background()
+	c, err := s.Client().ForUser(ctx, store, 99)
+	if err != nil {
+		t.Fatalf("ForUser() error = %v", err)
+	}
+	if _, err := c.UserService.GetMembershipDataForCurrentUser(ctx); err != nil {
+		t.Fatalf("GetMembershipDataForCurrentUser() error = %v", err)
+	}
+
+	if n := s.Requests("/App/OAuth/token"); n != 1 {
+		t.Errorf("token was refreshed %d times, want 1", n)
+	}
+	saved, _ := store.LoadToken(ctx, 99)
+	if saved.AccessToken == expired.AccessToken || saved.RefreshToken == expired.RefreshToken {
+		t.Errorf("store holds the expired token, want the refreshed token")
+	}
+	if !saved.Valid() {
+		t.Errorf("store holds an invalid token, want the refreshed token")
+	}
+}
+
+func TestForUserUsesValidToken(t *testing.T) {
+	s := destiny2test.NewServer()
+	defer s.Close()
+
+	user := destiny2.UserMembershipData{BungieNetUser: destiny2.GeneralUser{MembershipID: 99}}
+	token := s.IssueToken(user)
+	store := newMemoryTokenStore(99, token)
+
+	ctx := context.Background()
+	c, err := s.Client().ForUser(ctx, store, 99)
+	if err != nil {
+		t.Fatalf("ForUser() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.UserService.GetMembershipDataForCurrentUser(ctx); err != nil {
+			t.Fatalf("GetMembershipDataForCurrentUser() error = %v", err)
+		}
+	}
+
+	if n := s.Requests("/App/OAuth/token"); n != 0 {
+		t.Errorf("token was refreshed %d times, want 0", n)
+	}
+	if store.saves != 0 {
+		t.Errorf("token was saved %d times, want 0", store.saves)
+	}
+}
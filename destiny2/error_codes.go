@@ -0,0 +1,135 @@
+package destiny2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// PlatformErrorCode ...
+// https://bungie-net.github.io/multi/schema_Exceptions-PlatformErrorCodes.html#schema_Exceptions-PlatformErrorCodes
+type PlatformErrorCode int
+
+//go:generate go run ./internal/errorcodes -out error_codes_gen.go
+
+// String returns the name Bungie gives the code or PlatformErrorCode(n) if the code is not known
+func (c PlatformErrorCode) String() string {
+	if name, ok := platformErrorCodeNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("PlatformErrorCode(%d)", int(c))
+}
+
+// IsThrottled returns true if the code indicates that the request was rejected because a
+// throttle limit was exceeded
+func (c PlatformErrorCode) IsThrottled() bool {
+	switch c {
+	case CodeThrottleLimitExceeded,
+		CodeThrottleLimitExceededMinutes,
+		CodeThrottleLimitExceededMomentarily,
+		CodeThrottleLimitExceededSeconds,
+		CodePerEndpointRequestThrottleExceeded,
+		CodePerApplicationThrottleExceeded,
+		CodePerApplicationAnonymousThrottleExceeded,
+		CodePerApplicationAuthenticatedThrottleExceeded,
+		CodePerUserThrottleExceeded,
+		CodeDestinyThrottledByGameServer:
+		return true
+	}
+
+	return false
+}
+
+// IsMaintenance returns true if the code indicates that the API is down for maintenance
+func (c PlatformErrorCode) IsMaintenance() bool {
+	return c == CodeSystemDisabled
+}
+
+// IsAuthFailure returns true if the code indicates that the credentials used for the request
+// were missing, invalid or expired
+func (c PlatformErrorCode) IsAuthFailure() bool {
+	switch c {
+	case CodeAuthenticationInvalid,
+		CodeWebAuthModuleAsyncFailed,
+		CodeAuthTicketRequired,
+		CodeUnknownAuthenticationError,
+		CodeWebAuthRequired,
+		CodeAuthorizationCodeInvalid,
+		CodeAccessNotPermittedByApplicationScope,
+		CodeRefreshTokenNotYetValid,
+		CodeAccessTokenHasExpired,
+		CodeApplicationTokenFormatNotValid,
+		CodeOAuthAccessTokenExpired,
+		CodeApplicationTokenKeyIdDoesNotExist,
+		CodeProvidedTokenNotValidRefreshToken,
+		CodeRefreshTokenExpired:
+		return true
+	}
+
+	return false
+}
+
+// IsPrivacy returns true if the code indicates that the requested data is hidden by the
+// owner's privacy settings
+func (c PlatformErrorCode) IsPrivacy() bool {
+	return c == CodeDestinyPrivacyRestriction
+}
+
+// IsRetryable returns true if the code indicates a transient failure and the same request
+// may succeed if it is tried again
+func (c PlatformErrorCode) IsRetryable() bool {
+	switch c {
+	case CodeTransportException,
+		CodeExternalServiceTimeout,
+		CodeExternalServiceFailed,
+		CodeDestinyUnexpectedError:
+		return true
+	}
+
+	return c.IsThrottled()
+}
+
+// codeOf returns the platform error code of err if err is or wraps an APIError
+func codeOf(err error) (PlatformErrorCode, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return CodeNone, false
+	}
+
+	return apiErr.ErrorCode, true
+}
+
+// IsThrottled returns true if err is an APIError caused by exceeding a throttle limit
+func IsThrottled(err error) bool {
+	code, ok := codeOf(err)
+	return ok && code.IsThrottled()
+}
+
+// IsMaintenance returns true if err is an APIError caused by the API being down for maintenance
+func IsMaintenance(err error) bool {
+	code, ok := codeOf(err)
+	return ok && code.IsMaintenance()
+}
+
+// IsAuthFailure returns true if err is an APIError caused by missing, invalid or expired credentials
+func IsAuthFailure(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.ErrorCode.IsAuthFailure() || apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// IsPrivacy returns true if err is an APIError caused by the owner's privacy settings
+func IsPrivacy(err error) bool {
+	code, ok := codeOf(err)
+	return ok && code.IsPrivacy()
+}
+
+// IsRetryable returns true if err is an APIError caused by a transient failure
+func IsRetryable(err error) bool {
+	code, ok := codeOf(err)
+	return ok && code.IsRetryable()
+}
@@ -0,0 +1,188 @@
+// The codes in this file are a hand-maintained subset of the PlatformErrorCodes in the Bungie API's
+// OpenAPI spec, in the layout internal/errorcodes writes. They have not been generated from the spec yet;
+// run go generate ./destiny2 to replace this file with the full generated set.
+
+package destiny2
+
+// PlatformErrorCode values
+const (
+	CodeNone                                        PlatformErrorCode = 0
+	CodeSuccess                                     PlatformErrorCode = 1
+	CodeTransportException                          PlatformErrorCode = 2
+	CodeUnhandledException                          PlatformErrorCode = 3
+	CodeNotImplemented                              PlatformErrorCode = 4
+	CodeSystemDisabled                              PlatformErrorCode = 5
+	CodeFailedToLoadAvailableLocalesConfiguration   PlatformErrorCode = 6
+	CodeParameterParseFailure                       PlatformErrorCode = 7
+	CodeParameterInvalidRange                       PlatformErrorCode = 8
+	CodeBadRequest                                  PlatformErrorCode = 9
+	CodeAuthenticationInvalid                       PlatformErrorCode = 10
+	CodeDataNotFound                                PlatformErrorCode = 11
+	CodeInsufficientPrivileges                      PlatformErrorCode = 12
+	CodeDuplicate                                   PlatformErrorCode = 13
+	CodeUnknownSqlResult                            PlatformErrorCode = 14
+	CodeValidationError                             PlatformErrorCode = 15
+	CodeValidationMissingFieldError                 PlatformErrorCode = 16
+	CodeValidationInvalidInputError                 PlatformErrorCode = 17
+	CodeInvalidParameters                           PlatformErrorCode = 18
+	CodeParameterNotFound                           PlatformErrorCode = 19
+	CodeUnhandledHttpException                      PlatformErrorCode = 20
+	CodeNotFound                                    PlatformErrorCode = 21
+	CodeWebAuthModuleAsyncFailed                    PlatformErrorCode = 22
+	CodeInvalidReturnValue                          PlatformErrorCode = 23
+	CodeUserBanned                                  PlatformErrorCode = 24
+	CodeInvalidPostBody                             PlatformErrorCode = 25
+	CodeMissingPostBody                             PlatformErrorCode = 26
+	CodeExternalServiceTimeout                      PlatformErrorCode = 27
+	CodeValidationLengthError                       PlatformErrorCode = 28
+	CodeValidationRangeError                        PlatformErrorCode = 29
+	CodeJsonDeserializationError                    PlatformErrorCode = 30
+	CodeThrottleLimitExceeded                       PlatformErrorCode = 31
+	CodeValidationTagError                          PlatformErrorCode = 32
+	CodeValidationProfanityError                    PlatformErrorCode = 33
+	CodeValidationUrlFormatError                    PlatformErrorCode = 34
+	CodeThrottleLimitExceededMinutes                PlatformErrorCode = 35
+	CodeThrottleLimitExceededMomentarily            PlatformErrorCode = 36
+	CodeThrottleLimitExceededSeconds                PlatformErrorCode = 37
+	CodeExternalServiceUnknown                      PlatformErrorCode = 38
+	CodeValidationWordLengthError                   PlatformErrorCode = 39
+	CodeValidationInvisibleUnicode                  PlatformErrorCode = 40
+	CodeValidationBadNames                          PlatformErrorCode = 41
+	CodeExternalServiceFailed                       PlatformErrorCode = 42
+	CodeServiceRetired                              PlatformErrorCode = 43
+	CodeUnknownSqlException                         PlatformErrorCode = 44
+	CodeUnsupportedLanguage                         PlatformErrorCode = 45
+	CodeInvalidLanguage                             PlatformErrorCode = 46
+	CodeInvalidLocale                               PlatformErrorCode = 47
+	CodePerEndpointRequestThrottleExceeded          PlatformErrorCode = 51
+	CodePerApplicationThrottleExceeded              PlatformErrorCode = 54
+	CodePerApplicationAnonymousThrottleExceeded     PlatformErrorCode = 55
+	CodePerApplicationAuthenticatedThrottleExceeded PlatformErrorCode = 56
+	CodePerUserThrottleExceeded                     PlatformErrorCode = 57
+	CodeObsoleteCredentialType                      PlatformErrorCode = 89
+	CodeUnableToUnPairMobileApp                     PlatformErrorCode = 90
+	CodeUnableToPairMobileApp                       PlatformErrorCode = 91
+	CodeCannotUseMobileAuthWithNonMobileProvider    PlatformErrorCode = 92
+	CodeMissingDeviceCookie                         PlatformErrorCode = 93
+	CodeFacebookTokenExpired                        PlatformErrorCode = 94
+	CodeAuthTicketRequired                          PlatformErrorCode = 95
+	CodeCookieContextRequired                       PlatformErrorCode = 96
+	CodeUnknownAuthenticationError                  PlatformErrorCode = 97
+	CodeBungieNetAccountCreationRequired            PlatformErrorCode = 98
+	CodeWebAuthRequired                             PlatformErrorCode = 99
+	CodeContentUnknownSqlResult                     PlatformErrorCode = 100
+	CodeContentNeedUniquePath                       PlatformErrorCode = 101
+	CodeContentSqlException                         PlatformErrorCode = 102
+	CodeContentNotFound                             PlatformErrorCode = 103
+	CodeGroupNotFound                               PlatformErrorCode = 622
+	CodeDestinyAccountNotFound                      PlatformErrorCode = 1601
+	CodeDestinyUnexpectedError                      PlatformErrorCode = 1618
+	CodeDestinyPGCRNotFound                         PlatformErrorCode = 1653
+	CodeDestinyPrivacyRestriction                   PlatformErrorCode = 1665
+	CodeDestinyThrottledByGameServer                PlatformErrorCode = 1672
+	CodeApiInvalidOrExpiredKey                      PlatformErrorCode = 2101
+	CodeApiKeyMissingFromRequest                    PlatformErrorCode = 2102
+	CodeOriginHeaderDoesNotMatchKey                 PlatformErrorCode = 2103
+	CodeAuthorizationCodeInvalid                    PlatformErrorCode = 2106
+	CodeAccessNotPermittedByApplicationScope        PlatformErrorCode = 2108
+	CodeRefreshTokenNotYetValid                     PlatformErrorCode = 2110
+	CodeAccessTokenHasExpired                       PlatformErrorCode = 2111
+	CodeApplicationTokenFormatNotValid              PlatformErrorCode = 2112
+	CodeApplicationNotConfiguredForBungieAuth       PlatformErrorCode = 2113
+	CodeApplicationNotConfiguredForOAuth            PlatformErrorCode = 2114
+	CodeOAuthAccessTokenExpired                     PlatformErrorCode = 2115
+	CodeApplicationTokenKeyIdDoesNotExist           PlatformErrorCode = 2116
+	CodeProvidedTokenNotValidRefreshToken           PlatformErrorCode = 2117
+	CodeRefreshTokenExpired                         PlatformErrorCode = 2118
+)
+
+var platformErrorCodeNames = map[PlatformErrorCode]string{
+	CodeNone:               "None",
+	CodeSuccess:            "Success",
+	CodeTransportException: "TransportException",
+	CodeUnhandledException: "UnhandledException",
+	CodeNotImplemented:     "NotImplemented",
+	CodeSystemDisabled:     "SystemDisabled",
+	CodeFailedToLoadAvailableLocalesConfiguration:   "FailedToLoadAvailableLocalesConfiguration",
+	CodeParameterParseFailure:                       "ParameterParseFailure",
+	CodeParameterInvalidRange:                       "ParameterInvalidRange",
+	CodeBadRequest:                                  "BadRequest",
+	CodeAuthenticationInvalid:                       "AuthenticationInvalid",
+	CodeDataNotFound:                                "DataNotFound",
+	CodeInsufficientPrivileges:                      "InsufficientPrivileges",
+	CodeDuplicate:                                   "Duplicate",
+	CodeUnknownSqlResult:                            "UnknownSqlResult",
+	CodeValidationError:                             "ValidationError",
+	CodeValidationMissingFieldError:                 "ValidationMissingFieldError",
+	CodeValidationInvalidInputError:                 "ValidationInvalidInputError",
+	CodeInvalidParameters:                           "InvalidParameters",
+	CodeParameterNotFound:                           "ParameterNotFound",
+	CodeUnhandledHttpException:                      "UnhandledHttpException",
+	CodeNotFound:                                    "NotFound",
+	CodeWebAuthModuleAsyncFailed:                    "WebAuthModuleAsyncFailed",
+	CodeInvalidReturnValue:                          "InvalidReturnValue",
+	CodeUserBanned:                                  "UserBanned",
+	CodeInvalidPostBody:                             "InvalidPostBody",
+	CodeMissingPostBody:                             "MissingPostBody",
+	CodeExternalServiceTimeout:                      "ExternalServiceTimeout",
+	CodeValidationLengthError:                       "ValidationLengthError",
+	CodeValidationRangeError:                        "ValidationRangeError",
+	CodeJsonDeserializationError:                    "JsonDeserializationError",
+	CodeThrottleLimitExceeded:                       "ThrottleLimitExceeded",
+	CodeValidationTagError:                          "ValidationTagError",
+	CodeValidationProfanityError:                    "ValidationProfanityError",
+	CodeValidationUrlFormatError:                    "ValidationUrlFormatError",
+	CodeThrottleLimitExceededMinutes:                "ThrottleLimitExceededMinutes",
+	CodeThrottleLimitExceededMomentarily:            "ThrottleLimitExceededMomentarily",
+	CodeThrottleLimitExceededSeconds:                "ThrottleLimitExceededSeconds",
+	CodeExternalServiceUnknown:                      "ExternalServiceUnknown",
+	CodeValidationWordLengthError:                   "ValidationWordLengthError",
+	CodeValidationInvisibleUnicode:                  "ValidationInvisibleUnicode",
+	CodeValidationBadNames:                          "ValidationBadNames",
+	CodeExternalServiceFailed:                       "ExternalServiceFailed",
+	CodeServiceRetired:                              "ServiceRetired",
+	CodeUnknownSqlException:                         "UnknownSqlException",
+	CodeUnsupportedLanguage:                         "UnsupportedLanguage",
+	CodeInvalidLanguage:                             "InvalidLanguage",
+	CodeInvalidLocale:                               "InvalidLocale",
+	CodePerEndpointRequestThrottleExceeded:          "PerEndpointRequestThrottleExceeded",
+	CodePerApplicationThrottleExceeded:              "PerApplicationThrottleExceeded",
+	CodePerApplicationAnonymousThrottleExceeded:     "PerApplicationAnonymousThrottleExceeded",
+	CodePerApplicationAuthenticatedThrottleExceeded: "PerApplicationAuthenticatedThrottleExceeded",
+	CodePerUserThrottleExceeded:                     "PerUserThrottleExceeded",
+	CodeObsoleteCredentialType:                      "ObsoleteCredentialType",
+	CodeUnableToUnPairMobileApp:                     "UnableToUnPairMobileApp",
+	CodeUnableToPairMobileApp:                       "UnableToPairMobileApp",
+	CodeCannotUseMobileAuthWithNonMobileProvider:    "CannotUseMobileAuthWithNonMobileProvider",
+	CodeMissingDeviceCookie:                         "MissingDeviceCookie",
+	CodeFacebookTokenExpired:                        "FacebookTokenExpired",
+	CodeAuthTicketRequired:                          "AuthTicketRequired",
+	CodeCookieContextRequired:                       "CookieContextRequired",
+	CodeUnknownAuthenticationError:                  "UnknownAuthenticationError",
+	CodeBungieNetAccountCreationRequired:            "BungieNetAccountCreationRequired",
+	CodeWebAuthRequired:                             "WebAuthRequired",
+	CodeContentUnknownSqlResult:                     "ContentUnknownSqlResult",
+	CodeContentNeedUniquePath:                       "ContentNeedUniquePath",
+	CodeContentSqlException:                         "ContentSqlException",
+	CodeContentNotFound:                             "ContentNotFound",
+	CodeGroupNotFound:                               "GroupNotFound",
+	CodeDestinyAccountNotFound:                      "DestinyAccountNotFound",
+	CodeDestinyUnexpectedError:                      "DestinyUnexpectedError",
+	CodeDestinyPGCRNotFound:                         "DestinyPGCRNotFound",
+	CodeDestinyPrivacyRestriction:                   "DestinyPrivacyRestriction",
+	CodeDestinyThrottledByGameServer:                "DestinyThrottledByGameServer",
+	CodeApiInvalidOrExpiredKey:                      "ApiInvalidOrExpiredKey",
+	CodeApiKeyMissingFromRequest:                    "ApiKeyMissingFromRequest",
+	CodeOriginHeaderDoesNotMatchKey:                 "OriginHeaderDoesNotMatchKey",
+	CodeAuthorizationCodeInvalid:                    "AuthorizationCodeInvalid",
+	CodeAccessNotPermittedByApplicationScope:        "AccessNotPermittedByApplicationScope",
+	CodeRefreshTokenNotYetValid:                     "RefreshTokenNotYetValid",
+	CodeAccessTokenHasExpired:                       "AccessTokenHasExpired",
+	CodeApplicationTokenFormatNotValid:              "ApplicationTokenFormatNotValid",
+	CodeApplicationNotConfiguredForBungieAuth:       "ApplicationNotConfiguredForBungieAuth",
+	CodeApplicationNotConfiguredForOAuth:            "ApplicationNotConfiguredForOAuth",
+	CodeOAuthAccessTokenExpired:                     "OAuthAccessTokenExpired",
+	CodeApplicationTokenKeyIdDoesNotExist:           "ApplicationTokenKeyIdDoesNotExist",
+	CodeProvidedTokenNotValidRefreshToken:           "ProvidedTokenNotValidRefreshToken",
+	CodeRefreshTokenExpired:                         "RefreshTokenExpired",
+}
@@ -1,6 +1,10 @@
 package destiny2
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
 
 // SimpleError is returned for errors that have no metadata
 type SimpleError string
@@ -17,12 +21,87 @@ const (
 	// ErrUnautorized is returned when invalid/expired credentials are used
 	ErrUnautorized SimpleError = "Unauthorized"
 
-	// ErrUnknown is returned when the body of a response is not JSON and the
-	// status code is not handled
+	// ErrUnknown matches API errors that are not described by any of the
+	// other errors in this package
 	ErrUnknown SimpleError = "Unknown"
 
 	// ErrNotFound is returned when a requested resource could not be found.
 	// Also, weirdly, error can also be returned when an endpoint is hit with
 	// a method the endpoint is not expecting
 	ErrNotFound SimpleError = "NotFound"
+
+	// ErrNoComponents is returned when a profile is requested without any components
+	ErrNoComponents SimpleError = "NoComponents"
+
+	// ErrNoResponse is returned when middleware returns neither a response nor an error
+	ErrNoResponse SimpleError = "NoResponse"
+
+	// ErrNotModified is returned when the API answers 304 Not Modified to a request there is no cached
+	// response for
+	ErrNotModified SimpleError = "NotModified"
+
+	// ErrNoToken is returned when a TokenStore returns no token for a user
+	ErrNoToken SimpleError = "NoToken"
+
+	// ErrShortStateKey is returned when a StateManager is created with a key shorter than MinStateKeyLength
+	ErrShortStateKey SimpleError = "ShortStateKey"
+
+	// ErrInvalidState is returned when an OAuth state was not issued by the StateManager verifying it
+	ErrInvalidState SimpleError = "InvalidState"
+
+	// ErrStateExpired is returned when an OAuth state is verified after it has expired
+	ErrStateExpired SimpleError = "StateExpired"
+
+	// ErrStateReused is returned when an OAuth state is verified more than once
+	ErrStateReused SimpleError = "StateReused"
 )
+
+// APIError is returned when the Bungie API responds with an error. It preserves the error
+// envelope returned by Bungie along with the HTTP status code of the response.
+//
+// APIError can be compared to the sentinel errors in this package using errors.Is
+type APIError struct {
+	APIResponse
+	StatusCode int
+
+	// retryAfter is the value of the Retry-After header of the response, if it had one
+	retryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.ErrorStatus == "" {
+		return fmt.Sprintf("destiny2: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	return fmt.Sprintf("destiny2: %s (%d): %s", e.ErrorStatus, e.ErrorCode, e.Message)
+}
+
+// Is reports whether the error matches target. Used by errors.Is so that APIError can be
+// compared to the sentinel errors in this package
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(SimpleError)
+	if !ok {
+		return false
+	}
+
+	return t == e.sentinel()
+}
+
+// sentinel returns the sentinel error that best describes the error
+func (e *APIError) sentinel() SimpleError {
+	switch e.ErrorCode {
+	case CodeNotFound:
+		return ErrNotFound
+	case CodeWebAuthRequired:
+		return ErrWebAuthRequired
+	}
+
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnautorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	}
+
+	return ErrUnknown
+}
@@ -0,0 +1,130 @@
+package destiny2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// FlightStats counts the requests that were collapsed into a single call to the Bungie API
+type FlightStats struct {
+
+	// Requests is the number of requests that were eligible to be collapsed
+	Requests uint64
+
+	// Saved is the number of requests that shared the response of another request instead of
+	// calling the Bungie API themselves
+	Saved uint64
+}
+
+// flightGroup collapses identical concurrent requests into a single call
+type flightGroup struct {
+	mu       sync.Mutex
+	calls    map[string]*flightCall
+	requests uint64
+	saved    uint64
+}
+
+// flightCall is a call that is in flight or has completed
+type flightCall struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+// newFlightGroup creates an empty flight group
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: map[string]*flightCall{}}
+}
+
+// do calls fn for key, unless a call for key is already in flight in which case the caller waits
+// for that call to complete and shares its response
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (*Response, error)) (*Response, error) {
+	atomic.AddUint64(&g.requests, 1)
+
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+
+		// The call was cancelled by the caller that made it, not us, so we make the call ourselves
+		if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+			return fn()
+		}
+
+		atomic.AddUint64(&g.saved, 1)
+		return call.shared()
+	}
+
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.resp, call.err = fn()
+	close(call.done)
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return call.resp, call.err
+}
+
+// shared returns a copy of the call's response that is safe to hand to another caller
+func (c *flightCall) shared() (*Response, error) {
+	if c.resp == nil {
+		return nil, c.err
+	}
+
+	r := *c.resp
+	r.Header = r.Header.Clone()
+	return &r, c.err
+}
+
+// stats returns the number of requests that have been collapsed
+func (g *flightGroup) stats() FlightStats {
+	return FlightStats{
+		Requests: atomic.LoadUint64(&g.requests),
+		Saved:    atomic.LoadUint64(&g.saved),
+	}
+}
+
+// FlightStats returns how many identical concurrent requests have been collapsed into a single call
+// to the Bungie API. Stats are shared with every copy of the client made with Client.WithOAuth2Token
+func (c *Client) FlightStats() FlightStats {
+	return c.flights.stats()
+}
+
+// flightDoer wraps next so that identical concurrent requests for public data are only sent once
+func (c *Client) flightDoer(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*Response, error) {
+		if !c.shareable(req) {
+			return next.Do(req)
+		}
+
+		return c.flights.do(req.Context(), flightKey(req), func() (*Response, error) {
+			return next.Do(req)
+		})
+	})
+}
+
+// flightKey returns the key requests share a flight by. Conditional requests can be answered with a 304
+// that is only useful to callers holding the cached response, so their conditions are part of the key
+func flightKey(req *http.Request) string {
+	key := req.URL.String()
+	if etag := req.Header.Get("If-None-Match"); etag != "" {
+		key += "\nIf-None-Match: " + etag
+	}
+	if since := req.Header.Get("If-Modified-Since"); since != "" {
+		key += "\nIf-Modified-Since: " + since
+	}
+
+	return key
+}
@@ -1,35 +1,173 @@
 package destiny2
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"path"
 	"time"
 )
 
 // GroupV2Service is an interface for interfacing with the groupv2 endpoints
 // of the Bungie API.
+//
+// The admin endpoints, like ApprovePending and KickMember, require the client to be authenticated as an
+// admin of the group, either with Client.WithOAuth2Token, Client.ForUser or OptionOAuthToken. The token
+// must have been granted the AdminGroups scope
 // https://bungie-net.github.io/multi/operation_get_GroupV2-GetAvailableAvatars.html#operation_get_GroupV2-GetAvailableAvatars
 type GroupV2Service struct {
 	c *Client
 }
 
+// GroupType ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupType.html#schema_GroupsV2-GroupType
+type GroupType int
+
+// GroupType values
+const (
+	GroupTypeGeneral GroupType = 0
+	GroupTypeClan    GroupType = 1
+)
+
+// GroupsForMemberFilter ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupsForMemberFilter.html#schema_GroupsV2-GroupsForMemberFilter
+type GroupsForMemberFilter int
+
+// GroupsForMemberFilter values
+const (
+	GroupsForMemberFilterAll        GroupsForMemberFilter = 0
+	GroupsForMemberFilterFounded    GroupsForMemberFilter = 1
+	GroupsForMemberFilterNonFounded GroupsForMemberFilter = 2
+)
+
+// ClanLevelProgressionHash is the hash of the progression that tracks a clan's level
+const ClanLevelProgressionHash = 584850370
+
+// GroupResponse ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupResponse.html#schema_GroupsV2-GroupResponse
+type GroupResponse struct {
+	Detail                                   GroupV2                      `json:"detail"`
+	Founder                                  GroupMember                  `json:"founder"`
+	AlliedIDs                                Int64Slice                   `json:"alliedIds"`
+	ParentGroup                              *GroupV2                     `json:"parentGroup"`
+	AllianceStatus                           int                          `json:"allianceStatus"`
+	GroupJoinInviteCount                     int                          `json:"groupJoinInviteCount"`
+	CurrentUserMembershipsInactiveForDestiny bool                         `json:"currentUserMembershipsInactiveForDestiny"`
+	CurrentUserMemberMap                     map[int]GroupMember          `json:"currentUserMemberMap"`
+	CurrentUserPotentialMemberMap            map[int]GroupPotentialMember `json:"currentUserPotentialMemberMap"`
+}
+
+// GroupV2 ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupV2.html#schema_GroupsV2-GroupV2
+type GroupV2 struct {
+	GroupID                            int64                        `json:"groupId,string"`
+	Name                               string                       `json:"name"`
+	GroupType                          GroupType                    `json:"groupType"`
+	MembershipIDCreated                int64                        `json:"membershipIdCreated,string"`
+	CreationDate                       time.Time                    `json:"creationDate"`
+	ModificationDate                   time.Time                    `json:"modificationDate"`
+	About                              string                       `json:"about"`
+	Tags                               []string                     `json:"tags"`
+	MemberCount                        int                          `json:"memberCount"`
+	IsPublic                           bool                         `json:"isPublic"`
+	IsPublicTopicAdminOnly             bool                         `json:"isPublicTopicAdminOnly"`
+	Motto                              string                       `json:"motto"`
+	AllowChat                          bool                         `json:"allowChat"`
+	IsDefaultPostPublic                bool                         `json:"isDefaultPostPublic"`
+	ChatSecurity                       int                          `json:"chatSecurity"`
+	Locale                             string                       `json:"locale"`
+	AvatarImageIndex                   int                          `json:"avatarImageIndex"`
+	Homepage                           int                          `json:"homepage"`
+	MembershipOption                   int                          `json:"membershipOption"`
+	DefaultPublicity                   int                          `json:"defaultPublicity"`
+	Theme                              string                       `json:"theme"`
+	BannerPath                         string                       `json:"bannerPath"`
+	AvatarPath                         string                       `json:"avatarPath"`
+	ConversationID                     int64                        `json:"conversationId,string"`
+	EnableInvitationMessagingForAdmins bool                         `json:"enableInvitationMessagingForAdmins"`
+	BanExpireDate                      *time.Time                   `json:"banExpireDate"`
+	Features                           GroupFeatures                `json:"features"`
+	ClanInfo                           GroupV2ClanInfoAndInvestment `json:"clanInfo"`
+}
+
+// ClanLevel returns the level of the clan or 0 if the group is not a clan
+func (g GroupV2) ClanLevel() int {
+	return g.ClanInfo.D2ClanProgressions[ClanLevelProgressionHash].Level
+}
+
+// GroupFeatures ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupFeatures.html#schema_GroupsV2-GroupFeatures
+type GroupFeatures struct {
+	MaximumMembers                   int                    `json:"maximumMembers"`
+	MaximumMembershipsOfGroupType    int                    `json:"maximumMembershipsOfGroupType"`
+	Capabilities                     int                    `json:"capabilities"`
+	MembershipTypes                  []BungieMembershipType `json:"membershipTypes"`
+	InvitePermissionOverride         bool                   `json:"invitePermissionOverride"`
+	UpdateCulturePermissionOverride  bool                   `json:"updateCulturePermissionOverride"`
+	HostGuidedGamePermissionOverride int                    `json:"hostGuidedGamePermissionOverride"`
+	UpdateBannerPermissionOverride   bool                   `json:"updateBannerPermissionOverride"`
+	JoinLevel                        int                    `json:"joinLevel"`
+}
+
+// GroupV2ClanInfoAndInvestment ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupV2ClanInfoAndInvestment.html#schema_GroupsV2-GroupV2ClanInfoAndInvestment
+type GroupV2ClanInfoAndInvestment struct {
+	D2ClanProgressions map[uint]DestinyProgression `json:"d2ClanProgressions"`
+	ClanCallsign       string                      `json:"clanCallsign"`
+	ClanBannerData     ClanBanner                  `json:"clanBannerData"`
+}
+
+// ClanBanner ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-ClanBanner.html#schema_GroupsV2-ClanBanner
+type ClanBanner struct {
+	DecalID                uint `json:"decalId"`
+	DecalColorID           uint `json:"decalColorId"`
+	DecalBackgroundColorID uint `json:"decalBackgroundColorId"`
+	GonfalonID             uint `json:"gonfalonId"`
+	GonfalonColorID        uint `json:"gonfalonColorId"`
+	GonfalonDetailID       uint `json:"gonfalonDetailId"`
+	GonfalonDetailColorID  uint `json:"gonfalonDetailColorId"`
+}
+
+// GroupPotentialMember ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupPotentialMember.html#schema_GroupsV2-GroupPotentialMember
+type GroupPotentialMember struct {
+	PotentialStatus   int               `json:"potentialStatus"`
+	GroupID           int64             `json:"groupId,string"`
+	DestinyUserInfo   GroupUserInfoCard `json:"destinyUserInfo"`
+	BungieNetUserInfo UserInfoCard      `json:"bungieNetUserInfo"`
+	JoinDate          time.Time         `json:"joinDate"`
+}
+
+// GetGroupsForMemberResponse ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GetGroupsForMemberResponse.html#schema_GroupsV2-GetGroupsForMemberResponse
+type GetGroupsForMemberResponse struct {
+	AreAllMembershipsInactive map[string]bool   `json:"areAllMembershipsInactive"`
+	Results                   []GroupMembership `json:"results"`
+	SearchResult
+}
+
+// GroupMembership ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupMembership.html#schema_GroupsV2-GroupMembership
+type GroupMembership struct {
+	Member GroupMember `json:"member"`
+	Group  GroupV2     `json:"group"`
+}
+
 // SearchResultOfGroupMember ...
 // https://bungie-net.github.io/multi/schema_SearchResultOfGroupMember.html#schema_SearchResultOfGroupMember
 type SearchResultOfGroupMember struct {
-	Results                      []GroupMember `json:"results"`
-	TotalResults                 int           `json:"totalResults"`
-	HasMore                      bool          `json:"hasMore"`
-	Query                        PagedQuery    `json:"query"`
-	ReplacementContinuationToken string        `json:"replacementContinuationToken"`
+	Results []GroupMember `json:"results"`
+	SearchResult
 }
 
 // GroupMember ...
 // https://bungie-net.github.io/multi/schema_GroupsV2-GroupMember.html#schema_GroupsV2-GroupMember
 type GroupMember struct {
-	MemberType             int               `json:"memberType"`
+	MemberType             GroupMemberType   `json:"memberType"`
 	IsOnline               bool              `json:"isOnline"`
-	LastOnlineStatusChange int64             `json:"lastOnlineStatusChange"`
-	GroupID                int64             `json:"groudId"`
+	LastOnlineStatusChange int64             `json:"lastOnlineStatusChange,string"`
+	GroupID                int64             `json:"groupId,string"`
 	DestinyUserInfo        GroupUserInfoCard `json:"destinyUserInfo"`
 	JoinDate               time.Time         `json:"joinDate"`
 }
@@ -37,54 +175,114 @@ type GroupMember struct {
 // GroupUserInfoCard ...
 // https://bungie-net.github.io/multi/schema_GroupsV2-GroupUserInfoCard.html#schema_GroupsV2-GroupUserInfoCard
 type GroupUserInfoCard struct {
-	LastSeenDisplayName       string  `json:"LastSeenDisplayName"`
-	LastSeenDisplayNameType   int     `json:"LastSeenDisplayNameType"`
-	SupplementalDisplayName   string  `json:"supplementalDisplayName"`
-	IconPath                  string  `json:"iconPath"`
-	CrossSaveOverride         int     `json:"crossSaveOverride"`
-	ApplicableMembershipTypes []int32 `json:"applicableMembershipTypes"`
-	IsPublic                  bool    `json:"isPublic"`
-	MembershipType            int     `json:"membershipType"`
-	MembershipID              int64   `json:"membershipId,string"`
-	DisplayName               string  `json:"displayName"`
+	LastSeenDisplayName         string                 `json:"LastSeenDisplayName"`
+	LastSeenDisplayNameType     int                    `json:"LastSeenDisplayNameType"`
+	SupplementalDisplayName     string                 `json:"supplementalDisplayName"`
+	IconPath                    string                 `json:"iconPath"`
+	CrossSaveOverride           BungieMembershipType   `json:"crossSaveOverride"`
+	ApplicableMembershipTypes   []BungieMembershipType `json:"applicableMembershipTypes"`
+	IsPublic                    bool                   `json:"isPublic"`
+	MembershipType              BungieMembershipType   `json:"membershipType"`
+	MembershipID                int64                  `json:"membershipId,string"`
+	DisplayName                 string                 `json:"displayName"`
+	BungieGlobalDisplayName     string                 `json:"bungieGlobalDisplayName"`
+	BungieGlobalDisplayNameCode int                    `json:"bungieGlobalDisplayNameCode"`
+}
+
+// BungieName returns the user's Bungie Name in the name#1234 form, or an empty string if the user
+// has not been assigned one
+func (u GroupUserInfoCard) BungieName() string {
+	return FormatBungieName(u.BungieGlobalDisplayName, u.BungieGlobalDisplayNameCode)
+}
+
+// GetGroup gets information about a given group
+func (gs *GroupV2Service) GetGroup(ctx context.Context, gid int64, opts ...RequestOption) (GroupResponse, error) {
+	r := GroupResponse{}
+	endpoint := fmt.Sprintf("/%d", gid)
+	err := gs.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// GetGroupByName gets information about the group of the given type with the given name
+func (gs *GroupV2Service) GetGroupByName(ctx context.Context, name string, groupType GroupType, opts ...RequestOption) (GroupResponse, error) {
+	r := GroupResponse{}
+	endpoint := fmt.Sprintf("/Name/%s/%d", url.PathEscape(name), groupType)
+	err := gs.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// GetGroupsForMember gets the groups of the given type that the given membership has joined
+func (gs *GroupV2Service) GetGroupsForMember(ctx context.Context, membershipType BungieMembershipType, membershipID int64, filter GroupsForMemberFilter, groupType GroupType, opts ...RequestOption) (GetGroupsForMemberResponse, error) {
+	r := GetGroupsForMemberResponse{}
+	endpoint := fmt.Sprintf("/User/%d/%d/%d/%d", membershipType, membershipID, filter, groupType)
+	err := gs.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// IsMemberOfGroup reports whether the given membership is a member of the given clan
+func (gs *GroupV2Service) IsMemberOfGroup(ctx context.Context, membershipType BungieMembershipType, membershipID, gid int64, opts ...RequestOption) (bool, error) {
+	r, err := gs.GetGroupsForMember(ctx, membershipType, membershipID, GroupsForMemberFilterAll, GroupTypeClan, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	for _, membership := range r.Results {
+		if membership.Group.GroupID == gid {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 // GetMembersOfGroup gets a list of members in a given group
-func (gs *GroupV2Service) GetMembersOfGroup(gid int64, opts ...RequestOption) (SearchResultOfGroupMember, error) {
+func (gs *GroupV2Service) GetMembersOfGroup(ctx context.Context, gid int64, opts ...RequestOption) (SearchResultOfGroupMember, error) {
 	r := SearchResultOfGroupMember{}
 	endpoint := fmt.Sprintf("/%d/Members", gid)
-	err := gs.do("GET", endpoint, &r, opts...)
+	err := gs.do(ctx, "GET", endpoint, &r, opts...)
 	return r, err
 }
 
 // GetAllMembersOfGroup gets all the members of a given group and paginates through pages if needed.
-func (gs *GroupV2Service) GetAllMembersOfGroup(gid int64, opts ...RequestOption) ([]GroupMember, error) {
+func (gs *GroupV2Service) GetAllMembersOfGroup(ctx context.Context, gid int64, opts ...RequestOption) ([]GroupMember, error) {
 	members := []GroupMember{}
 
 	// Looping until no more pages or unrecoverable error
-	for page := 1; ; page++ {
-		// Adding/overriding page query param
-		opts = append(opts, OptionQuery("currentPage", page))
-
-		// Getting page of memeber
-		resp, err := gs.GetMembersOfGroup(gid, opts...)
-		if err != nil {
-			return nil, err
-		}
-
-		// Adding members to array
-		members = append(members, resp.Results...)
-
-		// Checking if there are more members to get
-		if !resp.HasMore {
-			break
-		}
+	p := gs.MembersOfGroupPager(gid, opts...)
+	for p.Next(ctx) {
+		members = append(members, p.Results()...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
 	}
 
 	return members, nil
 }
 
-func (gs *GroupV2Service) do(method, endpoint string, dst interface{}, opts ...RequestOption) error {
+// GroupMemberPager steps through pages of group members
+type GroupMemberPager struct {
+	*Pager
+	page SearchResultOfGroupMember
+}
+
+// Results returns the members in the page last fetched by GroupMemberPager.Next
+func (p *GroupMemberPager) Results() []GroupMember {
+	return p.page.Results
+}
+
+// MembersOfGroupPager returns a pager that steps through the members of a given group one page at a time
+func (gs *GroupV2Service) MembersOfGroupPager(gid int64, opts ...RequestOption) *GroupMemberPager {
+	p := &GroupMemberPager{}
+	p.Pager = NewPager(func(ctx context.Context, opts ...RequestOption) (SearchResult, error) {
+		var err error
+		p.page, err = gs.GetMembersOfGroup(ctx, gid, opts...)
+		return p.page.SearchResult, err
+	}, opts...)
+
+	return p
+}
+
+func (gs *GroupV2Service) do(ctx context.Context, method, endpoint string, dst interface{}, opts ...RequestOption) error {
 	endpoint = path.Join("/GroupV2", endpoint)
-	return gs.c.do(method, endpoint, dst, opts...)
+	return gs.c.do(ctx, method, endpoint, dst, opts...)
 }
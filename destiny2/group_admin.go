@@ -0,0 +1,229 @@
+package destiny2
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GroupMemberApplication ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupMemberApplication.html#schema_GroupsV2-GroupMemberApplication
+type GroupMemberApplication struct {
+	GroupID                int64             `json:"groupId,string"`
+	CreationDate           time.Time         `json:"creationDate"`
+	ResolveState           int               `json:"resolveState"`
+	ResolveDate            *time.Time        `json:"resolveDate"`
+	ResolvedByMembershipID int64             `json:"resolvedByMembershipId,string"`
+	RequestMessage         string            `json:"requestMessage"`
+	ResolveMessage         string            `json:"resolveMessage"`
+	DestinyUserInfo        GroupUserInfoCard `json:"destinyUserInfo"`
+	BungieNetUserInfo      UserInfoCard      `json:"bungieNetUserInfo"`
+}
+
+// SearchResultOfGroupMemberApplication ...
+// https://bungie-net.github.io/multi/schema_SearchResultOfGroupMemberApplication.html#schema_SearchResultOfGroupMemberApplication
+type SearchResultOfGroupMemberApplication struct {
+	Results []GroupMemberApplication `json:"results"`
+	SearchResult
+}
+
+// GroupBan ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupBan.html#schema_GroupsV2-GroupBan
+type GroupBan struct {
+	GroupID           int64             `json:"groupId,string"`
+	LastModifiedBy    UserInfoCard      `json:"lastModifiedBy"`
+	CreatedBy         UserInfoCard      `json:"createdBy"`
+	DateBanned        time.Time         `json:"dateBanned"`
+	DateExpires       time.Time         `json:"dateExpires"`
+	Comment           string            `json:"comment"`
+	BungieNetUserInfo UserInfoCard      `json:"bungieNetUserInfo"`
+	DestinyUserInfo   GroupUserInfoCard `json:"destinyUserInfo"`
+}
+
+// SearchResultOfGroupBan ...
+// https://bungie-net.github.io/multi/schema_SearchResultOfGroupBan.html#schema_SearchResultOfGroupBan
+type SearchResultOfGroupBan struct {
+	Results []GroupBan `json:"results"`
+	SearchResult
+}
+
+// UserMembership ...
+// https://bungie-net.github.io/multi/schema_User-UserMembership.html#schema_User-UserMembership
+type UserMembership struct {
+	MembershipType              BungieMembershipType `json:"membershipType"`
+	MembershipID                int64                `json:"membershipId,string"`
+	DisplayName                 string               `json:"displayName"`
+	BungieGlobalDisplayName     string               `json:"bungieGlobalDisplayName"`
+	BungieGlobalDisplayNameCode int                  `json:"bungieGlobalDisplayNameCode"`
+}
+
+// GroupApplicationRequest ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupApplicationRequest.html#schema_GroupsV2-GroupApplicationRequest
+type GroupApplicationRequest struct {
+	Message string `json:"message"`
+}
+
+// GroupApplicationListRequest ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupApplicationListRequest.html#schema_GroupsV2-GroupApplicationListRequest
+type GroupApplicationListRequest struct {
+	Memberships []UserMembership `json:"memberships"`
+	Message     string           `json:"message"`
+}
+
+// GroupApplicationResponse ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupApplicationResponse.html#schema_GroupsV2-GroupApplicationResponse
+type GroupApplicationResponse struct {
+	Resolution int `json:"resolution"`
+}
+
+// GroupBanRequest ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupBanRequest.html#schema_GroupsV2-GroupBanRequest
+type GroupBanRequest struct {
+	Comment string `json:"comment"`
+	Length  int    `json:"length"`
+}
+
+// GroupMemberLeaveResult ...
+// https://bungie-net.github.io/multi/schema_GroupsV2-GroupMemberLeaveResult.html#schema_GroupsV2-GroupMemberLeaveResult
+type GroupMemberLeaveResult struct {
+	Group        GroupV2 `json:"group"`
+	GroupDeleted bool    `json:"groupDeleted"`
+}
+
+// EntityActionResult ...
+// https://bungie-net.github.io/multi/schema_Entities-EntityActionResult.html#schema_Entities-EntityActionResult
+type EntityActionResult struct {
+	EntityID int64             `json:"entityId,string"`
+	Result   PlatformErrorCode `json:"result"`
+}
+
+// GetPendingMemberships gets the applications to a given group that have not been resolved yet
+func (gs *GroupV2Service) GetPendingMemberships(ctx context.Context, gid int64, opts ...RequestOption) (SearchResultOfGroupMemberApplication, error) {
+	r := SearchResultOfGroupMemberApplication{}
+	endpoint := fmt.Sprintf("/%d/Members/Pending", gid)
+	err := gs.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// GetInvitedIndividuals gets the users that have been invited to a given group but have not joined yet
+func (gs *GroupV2Service) GetInvitedIndividuals(ctx context.Context, gid int64, opts ...RequestOption) (SearchResultOfGroupMemberApplication, error) {
+	r := SearchResultOfGroupMemberApplication{}
+	endpoint := fmt.Sprintf("/%d/Members/InvitedIndividuals", gid)
+	err := gs.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// GetBannedMembersOfGroup gets the users that are banned from a given group
+func (gs *GroupV2Service) GetBannedMembersOfGroup(ctx context.Context, gid int64, opts ...RequestOption) (SearchResultOfGroupBan, error) {
+	r := SearchResultOfGroupBan{}
+	endpoint := fmt.Sprintf("/%d/Banned", gid)
+	err := gs.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// ApprovePending approves the pending application of the given membership to a given group
+func (gs *GroupV2Service) ApprovePending(ctx context.Context, gid int64, membershipType BungieMembershipType, membershipID int64, message string, opts ...RequestOption) (bool, error) {
+	var r bool
+	endpoint := fmt.Sprintf("/%d/Members/Approve/%d/%d", gid, membershipType, membershipID)
+	err := gs.post(ctx, endpoint, GroupApplicationRequest{Message: message}, &r, opts...)
+	return r, err
+}
+
+// ApprovePendingForList approves the pending applications of the given memberships to a given group
+func (gs *GroupV2Service) ApprovePendingForList(ctx context.Context, gid int64, memberships []UserMembership, message string, opts ...RequestOption) ([]EntityActionResult, error) {
+	r := []EntityActionResult{}
+	endpoint := fmt.Sprintf("/%d/Members/ApproveList", gid)
+	body := GroupApplicationListRequest{Memberships: memberships, Message: message}
+	err := gs.post(ctx, endpoint, body, &r, opts...)
+	return r, err
+}
+
+// ApproveAllPending approves all the pending applications to a given group
+func (gs *GroupV2Service) ApproveAllPending(ctx context.Context, gid int64, message string, opts ...RequestOption) ([]EntityActionResult, error) {
+	r := []EntityActionResult{}
+	endpoint := fmt.Sprintf("/%d/Members/ApproveAll", gid)
+	err := gs.post(ctx, endpoint, GroupApplicationRequest{Message: message}, &r, opts...)
+	return r, err
+}
+
+// DenyPendingForList denies the pending applications of the given memberships to a given group
+func (gs *GroupV2Service) DenyPendingForList(ctx context.Context, gid int64, memberships []UserMembership, message string, opts ...RequestOption) ([]EntityActionResult, error) {
+	r := []EntityActionResult{}
+	endpoint := fmt.Sprintf("/%d/Members/DenyList", gid)
+	body := GroupApplicationListRequest{Memberships: memberships, Message: message}
+	err := gs.post(ctx, endpoint, body, &r, opts...)
+	return r, err
+}
+
+// DenyAllPending denies all the pending applications to a given group
+func (gs *GroupV2Service) DenyAllPending(ctx context.Context, gid int64, message string, opts ...RequestOption) ([]EntityActionResult, error) {
+	r := []EntityActionResult{}
+	endpoint := fmt.Sprintf("/%d/Members/DenyAll", gid)
+	err := gs.post(ctx, endpoint, GroupApplicationRequest{Message: message}, &r, opts...)
+	return r, err
+}
+
+// KickMember kicks the given membership from a given group
+func (gs *GroupV2Service) KickMember(ctx context.Context, gid int64, membershipType BungieMembershipType, membershipID int64, opts ...RequestOption) (GroupMemberLeaveResult, error) {
+	r := GroupMemberLeaveResult{}
+	endpoint := fmt.Sprintf("/%d/Members/%d/%d/Kick", gid, membershipType, membershipID)
+	err := gs.post(ctx, endpoint, nil, &r, opts...)
+	return r, err
+}
+
+// BanMember bans the given membership from a given group. The member is not kicked from the group
+// so KickMember should also be called if they are a member
+func (gs *GroupV2Service) BanMember(ctx context.Context, gid int64, membershipType BungieMembershipType, membershipID int64, ban GroupBanRequest, opts ...RequestOption) (int, error) {
+	var r int
+	endpoint := fmt.Sprintf("/%d/Members/%d/%d/Ban", gid, membershipType, membershipID)
+	err := gs.post(ctx, endpoint, ban, &r, opts...)
+	return r, err
+}
+
+// UnbanMember lifts the ban of the given membership from a given group
+func (gs *GroupV2Service) UnbanMember(ctx context.Context, gid int64, membershipType BungieMembershipType, membershipID int64, opts ...RequestOption) (int, error) {
+	var r int
+	endpoint := fmt.Sprintf("/%d/Members/%d/%d/Unban", gid, membershipType, membershipID)
+	err := gs.post(ctx, endpoint, nil, &r, opts...)
+	return r, err
+}
+
+// IndividualGroupInvite invites the given membership to join a given group
+func (gs *GroupV2Service) IndividualGroupInvite(ctx context.Context, gid int64, membershipType BungieMembershipType, membershipID int64, message string, opts ...RequestOption) (GroupApplicationResponse, error) {
+	r := GroupApplicationResponse{}
+	endpoint := fmt.Sprintf("/%d/Members/IndividualInvite/%d/%d", gid, membershipType, membershipID)
+	err := gs.post(ctx, endpoint, GroupApplicationRequest{Message: message}, &r, opts...)
+	return r, err
+}
+
+// IndividualGroupInviteCancel cancels the invite sent to the given membership to join a given group
+func (gs *GroupV2Service) IndividualGroupInviteCancel(ctx context.Context, gid int64, membershipType BungieMembershipType, membershipID int64, opts ...RequestOption) (GroupApplicationResponse, error) {
+	r := GroupApplicationResponse{}
+	endpoint := fmt.Sprintf("/%d/Members/IndividualInviteCancel/%d/%d", gid, membershipType, membershipID)
+	err := gs.post(ctx, endpoint, nil, &r, opts...)
+	return r, err
+}
+
+// EditGroupMembership changes the member type of the given membership in a given group. Members can
+// not be promoted to or demoted from founder with this endpoint
+func (gs *GroupV2Service) EditGroupMembership(ctx context.Context, gid int64, membershipType BungieMembershipType, membershipID int64, memberType GroupMemberType, opts ...RequestOption) (int, error) {
+	var r int
+	endpoint := fmt.Sprintf("/%d/Members/%d/%d/SetMembershipType/%d", gid, membershipType, membershipID, memberType)
+	err := gs.post(ctx, endpoint, nil, &r, opts...)
+	return r, err
+}
+
+// post makes a POST request to the endpoint with body encoded as JSON. If body is nil the request is
+// sent without a body
+func (gs *GroupV2Service) post(ctx context.Context, endpoint string, body interface{}, dst interface{}, opts ...RequestOption) error {
+	if body != nil {
+		opt, err := OptionJSONBody(body)
+		if err != nil {
+			return err
+		}
+
+		opts = append([]RequestOption{opt}, opts...)
+	}
+
+	return gs.do(ctx, "POST", endpoint, dst, opts...)
+}
@@ -0,0 +1,127 @@
+// Command errorcodes generates the PlatformErrorCode enum of the destiny2 package from the Bungie API's
+// OpenAPI spec. It is run with go generate from the destiny2 package:
+//
+//	go generate ./destiny2
+//
+// The spec is read from the URL or file passed with -spec
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultSpec is the location of the Bungie API's OpenAPI spec
+const DefaultSpec = "https://raw.githubusercontent.com/Bungie-net/api/master/openapi.json"
+
+// schemaName is the name of the PlatformErrorCodes schema in the spec
+const schemaName = "Exceptions.PlatformErrorCodes"
+
+// spec is the part of the OpenAPI spec needed to generate the enum
+type spec struct {
+	Components struct {
+		Schemas map[string]struct {
+			EnumValues []enumValue `json:"x-enum-values"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+// enumValue is a single value of an enum in the spec
+type enumValue struct {
+	Identifier   string      `json:"identifier"`
+	NumericValue json.Number `json:"numericValue"`
+}
+
+func main() {
+	specPath := flag.String("spec", DefaultSpec, "URL or path of the Bungie API's OpenAPI spec")
+	out := flag.String("out", "error_codes_gen.go", "file the enum is written to")
+	flag.Parse()
+
+	values, err := load(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(values)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load reads the PlatformErrorCodes enum values from the spec at path, sorted by their value
+func load(path string) ([]enumValue, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		resp, err := http.Get(path)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching spec: %s", resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		r = f
+	}
+	defer r.Close()
+
+	s := spec{}
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+
+	values := s.Components.Schemas[schemaName].EnumValues
+	if len(values) == 0 {
+		return nil, fmt.Errorf("spec has no values for %s", schemaName)
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		a, _ := values[i].NumericValue.Int64()
+		b, _ := values[j].NumericValue.Int64()
+		return a < b
+	})
+
+	return values, nil
+}
+
+// generate returns the formatted source of the enum
+func generate(values []enumValue) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "// Code generated by internal/errorcodes from the Bungie API's OpenAPI spec. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package destiny2")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// PlatformErrorCode values")
+	fmt.Fprintln(buf, "const (")
+	for _, v := range values {
+		fmt.Fprintf(buf, "\tCode%s PlatformErrorCode = %s\n", v.Identifier, v.NumericValue)
+	}
+	fmt.Fprintln(buf, ")")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "var platformErrorCodeNames = map[PlatformErrorCode]string{")
+	for _, v := range values {
+		fmt.Fprintf(buf, "\tCode%s: %q,\n", v.Identifier, v.Identifier)
+	}
+	fmt.Fprintln(buf, "}")
+
+	return format.Source(buf.Bytes())
+}
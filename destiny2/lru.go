@@ -0,0 +1,66 @@
+package destiny2
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a fixed size, least recently used cache that is safe to use from multiple go routines
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[interface{}]*list.Element
+}
+
+// lruEntry is the value stored in each element of the cache's list
+type lruEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// newLRU creates an LRU cache that holds up to capacity items
+func newLRU(capacity int) *lru {
+	return &lru{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    map[interface{}]*list.Element{},
+	}
+}
+
+// get returns the value stored under key and marks it as recently used
+func (c *lru) get(key interface{}) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+
+	return e.Value.(*lruEntry).value, true
+}
+
+// add stores value under key, evicting the least recently used item if the cache is full
+func (c *lru) add(key, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*lruEntry).value = value
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry{key, value})
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
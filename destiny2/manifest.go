@@ -0,0 +1,184 @@
+package destiny2
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// Registering the sqlite3 driver used to read the world content database
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ManifestService is an interface for downloading and caching the Destiny 2 manifest.
+// https://bungie-net.github.io/multi/operation_get_Destiny2-GetDestinyManifest.html#operation_get_Destiny2-GetDestinyManifest
+type ManifestService struct {
+	c *Client
+}
+
+// DestinyManifest ...
+// https://bungie-net.github.io/multi/schema_Destiny-Config-DestinyManifest.html#schema_Destiny-Config-DestinyManifest
+type DestinyManifest struct {
+	Version                        string                        `json:"version"`
+	MobileAssetContentPath         string                        `json:"mobileAssetContentPath"`
+	MobileGearAssetDataBases       []GearAssetDataBaseDefinition `json:"mobileGearAssetDataBases"`
+	MobileWorldContentPaths        map[string]string             `json:"mobileWorldContentPaths"`
+	JSONWorldContentPaths          map[string]string             `json:"jsonWorldContentPaths"`
+	JSONWorldComponentContentPaths map[string]map[string]string  `json:"jsonWorldComponentContentPaths"`
+	MobileClanBannerDatabasePath   string                        `json:"mobileClanBannerDatabasePath"`
+	MobileGearCDN                  map[string]string             `json:"mobileGearCDN"`
+	IconImagePyramidInfo           []ImagePyramidEntry           `json:"iconImagePyramidInfo"`
+}
+
+// GearAssetDataBaseDefinition ...
+// https://bungie-net.github.io/multi/schema_Destiny-Config-GearAssetDataBaseDefinition.html#schema_Destiny-Config-GearAssetDataBaseDefinition
+type GearAssetDataBaseDefinition struct {
+	Version int    `json:"version"`
+	Path    string `json:"path"`
+}
+
+// ImagePyramidEntry ...
+// https://bungie-net.github.io/multi/schema_Destiny-Config-ImagePyramidEntry.html#schema_Destiny-Config-ImagePyramidEntry
+type ImagePyramidEntry struct {
+	Name   string  `json:"name"`
+	Factor float32 `json:"factor"`
+}
+
+// Manifest is a local copy of the world content database for a single language
+type Manifest struct {
+	Version  string
+	Language string
+	db       *sql.DB
+	cache    *lru
+}
+
+// Close closes the underlying database
+func (m *Manifest) Close() error {
+	return m.db.Close()
+}
+
+// GetManifest returns the current version of the manifest
+func (ms *ManifestService) GetManifest(ctx context.Context, opts ...RequestOption) (DestinyManifest, error) {
+	r := DestinyManifest{}
+	err := ms.c.do(ctx, "GET", "/Destiny2/Manifest", &r, opts...)
+	return r, err
+}
+
+// Sync makes sure dir contains the latest version of the world content database for the provided language
+// and opens it. The database is only downloaded if the version in dir is out of date
+func (ms *ManifestService) Sync(ctx context.Context, dir, language string, opts ...RequestOption) (*Manifest, error) {
+	manifest, err := ms.GetManifest(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	contentPath, ok := manifest.MobileWorldContentPaths[language]
+	if !ok {
+		return nil, fmt.Errorf("destiny2: no world content for language '%s'", language)
+	}
+
+	dir = filepath.Join(dir, language)
+	dbPath := filepath.Join(dir, "world.content")
+	versionPath := filepath.Join(dir, "version")
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// Only downloading the database if the one we have is out of date or missing
+	version, err := ioutil.ReadFile(versionPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if _, statErr := os.Stat(dbPath); string(version) != manifest.Version || statErr != nil {
+		if err = ms.download(ctx, contentPath, dbPath); err != nil {
+			return nil, err
+		}
+		if err = ioutil.WriteFile(versionPath, []byte(manifest.Version), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manifest{
+		Version:  manifest.Version,
+		Language: language,
+		db:       db,
+		cache:    newLRU(DefaultDefinitionCacheSize),
+	}, nil
+}
+
+// download downloads the zipped database at contentPath and extracts it to dst. The database is a
+// static asset so it is requested without the API key or any credentials
+func (ms *ManifestService) download(ctx context.Context, contentPath, dst string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", ms.c.siteURL+contentPath, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ms.c.plainHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode}
+	}
+
+	// Zip files need to be read from disk so the archive is saved to a temp file first
+	tmpZip, err := ioutil.TempFile(filepath.Dir(dst), "manifest-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpZip.Name())
+	defer tmpZip.Close()
+
+	if _, err = io.Copy(tmpZip, resp.Body); err != nil {
+		return err
+	}
+
+	zr, err := zip.OpenReader(tmpZip.Name())
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	if len(zr.File) == 0 || strings.HasSuffix(zr.File[0].Name, "/") {
+		return errors.New("destiny2: manifest archive does not contain a database")
+	}
+
+	// Extracting to a temp file and moving it into place so an open database is never
+	// left half written
+	src, err := zr.File[0].Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpDB, err := ioutil.TempFile(filepath.Dir(dst), "manifest-*.content")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpDB.Name())
+
+	if _, err = io.Copy(tmpDB, src); err != nil {
+		tmpDB.Close()
+		return err
+	}
+	if err = tmpDB.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpDB.Name(), dst)
+}
@@ -0,0 +1,250 @@
+package destiny2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BungieMembershipType is the platform a membership belongs to
+// https://bungie-net.github.io/multi/schema_BungieMembershipType.html#schema_BungieMembershipType
+type BungieMembershipType int
+
+// BungieMembershipType values
+const (
+	MembershipTypeAll        BungieMembershipType = -1
+	MembershipTypeNone       BungieMembershipType = 0
+	MembershipTypeXbox       BungieMembershipType = 1
+	MembershipTypePSN        BungieMembershipType = 2
+	MembershipTypeSteam      BungieMembershipType = 3
+	MembershipTypeBlizzard   BungieMembershipType = 4
+	MembershipTypeStadia     BungieMembershipType = 5
+	MembershipTypeEpic       BungieMembershipType = 6
+	MembershipTypeDemon      BungieMembershipType = 10
+	MembershipTypeBungieNext BungieMembershipType = 254
+)
+
+// membershipTypes holds the name and icon of every known membership type
+var membershipTypes = map[BungieMembershipType]struct {
+	name string
+	icon string
+}{
+	MembershipTypeAll:        {"All", ""},
+	MembershipTypeNone:       {"None", ""},
+	MembershipTypeXbox:       {"Xbox", "/img/theme/bungienet/icons/xboxLiveLogo.png"},
+	MembershipTypePSN:        {"PlayStation", "/img/theme/bungienet/icons/psnLogo.png"},
+	MembershipTypeSteam:      {"Steam", "/img/theme/bungienet/icons/steamLogo.png"},
+	MembershipTypeBlizzard:   {"Battle.net", "/img/theme/bungienet/icons/blizzardLogo.png"},
+	MembershipTypeStadia:     {"Stadia", "/img/theme/bungienet/icons/stadiaLogo.png"},
+	MembershipTypeEpic:       {"Epic Games", "/img/theme/bungienet/icons/egsLogo.png"},
+	MembershipTypeDemon:      {"Demon", ""},
+	MembershipTypeBungieNext: {"Bungie.net", ""},
+}
+
+// membershipTypeAliases holds the names ParseBungieMembershipType accepts for every membership type. It
+// is a slice so aliases are always checked in the same order
+var membershipTypeAliases = []struct {
+	membershipType BungieMembershipType
+	aliases        []string
+}{
+	{MembershipTypeAll, []string{"all", "any"}},
+	{MembershipTypeNone, []string{"none"}},
+	{MembershipTypeXbox, []string{"xbox", "xbl", "xb", "tigerxbox"}},
+	{MembershipTypePSN, []string{"playstation", "psn", "ps", "tigerpsn"}},
+	{MembershipTypeSteam, []string{"steam", "pc", "tigersteam"}},
+	{MembershipTypeBlizzard, []string{"battle.net", "blizzard", "bnet", "tigerblizzard"}},
+	{MembershipTypeStadia, []string{"stadia", "tigerstadia"}},
+	{MembershipTypeEpic, []string{"epic games", "epic", "egs", "tigeregs"}},
+	{MembershipTypeDemon, []string{"demon", "tigerdemon"}},
+	{MembershipTypeBungieNext, []string{"bungie.net", "bungienext", "bungie"}},
+}
+
+// String returns the name of the platform the membership type belongs to
+func (t BungieMembershipType) String() string {
+	if m, ok := membershipTypes[t]; ok {
+		return m.name
+	}
+
+	return fmt.Sprintf("BungieMembershipType(%d)", int(t))
+}
+
+// Icon returns the path of the platform's icon relative to the Bungie website or an empty string if
+// the platform does not have one
+func (t BungieMembershipType) Icon() string {
+	return membershipTypes[t].icon
+}
+
+// IconURL returns the full URL of the platform's icon or an empty string if the platform does not
+// have one
+func (t BungieMembershipType) IconURL() string {
+	if icon := t.Icon(); icon != "" {
+		return SiteURL + icon
+	}
+
+	return ""
+}
+
+// ParseBungieMembershipType parses a membership type from its number, name or a common alias of the
+// platform (eg. "psn", "pc") ignoring case
+func ParseBungieMembershipType(s string) (BungieMembershipType, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := membershipTypes[BungieMembershipType(n)]; ok {
+			return BungieMembershipType(n), nil
+		}
+	}
+
+	for _, m := range membershipTypeAliases {
+		for _, alias := range m.aliases {
+			if s == alias {
+				return m.membershipType, nil
+			}
+		}
+	}
+
+	return MembershipTypeNone, fmt.Errorf("unknown membership type %q", s)
+}
+
+// GroupMemberType is the rank of a member in a group
+// https://bungie-net.github.io/multi/schema_GroupsV2-RuntimeGroupMemberType.html#schema_GroupsV2-RuntimeGroupMemberType
+type GroupMemberType int
+
+// GroupMemberType values
+const (
+	MemberTypeNone          GroupMemberType = 0
+	MemberTypeBeginner      GroupMemberType = 1
+	MemberTypeMember        GroupMemberType = 2
+	MemberTypeAdmin         GroupMemberType = 3
+	MemberTypeActingFounder GroupMemberType = 4
+	MemberTypeFounder       GroupMemberType = 5
+)
+
+// memberTypeNames holds the name of every member type
+var memberTypeNames = map[GroupMemberType]string{
+	MemberTypeNone:          "None",
+	MemberTypeBeginner:      "Beginner",
+	MemberTypeMember:        "Member",
+	MemberTypeAdmin:         "Admin",
+	MemberTypeActingFounder: "Acting Founder",
+	MemberTypeFounder:       "Founder",
+}
+
+// String returns the name of the member type
+func (t GroupMemberType) String() string {
+	if name, ok := memberTypeNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("GroupMemberType(%d)", int(t))
+}
+
+// IsAdmin returns true if members of the type can administer the group
+func (t GroupMemberType) IsAdmin() bool {
+	return t >= MemberTypeAdmin
+}
+
+// ParseGroupMemberType parses a member type from its number or name ignoring case and spaces
+func ParseGroupMemberType(s string) (GroupMemberType, error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), ""))
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := memberTypeNames[GroupMemberType(n)]; ok {
+			return GroupMemberType(n), nil
+		}
+	}
+
+	for t, name := range memberTypeNames {
+		if s == strings.ToLower(strings.ReplaceAll(name, " ", "")) {
+			return t, nil
+		}
+	}
+
+	return MemberTypeNone, fmt.Errorf("unknown member type %q", s)
+}
+
+// PrimaryMembership returns the membership the user plays Destiny 2 on. If the user has cross save
+// enabled that is the membership every other membership is overridden by. If cross save is not
+// enabled, or the overriding membership is not among cards, the only membership is returned. False
+// is returned if there are no memberships, or if the primary can't be found and there are multiple
+// memberships since any of them could be played on
+func PrimaryMembership(cards []UserInfoCard) (UserInfoCard, bool) {
+	for _, card := range cards {
+		if card.CrossSaveOverride == MembershipTypeNone {
+			continue
+		}
+
+		// Cross save is active so the primary membership is the one the others are overridden by
+		for _, primary := range cards {
+			if primary.MembershipType == card.CrossSaveOverride {
+				return primary, true
+			}
+		}
+	}
+
+	if len(cards) == 1 {
+		return cards[0], true
+	}
+
+	return UserInfoCard{}, false
+}
+
+// UserInfoCard returns the card as a UserInfoCard
+func (u GroupUserInfoCard) UserInfoCard() UserInfoCard {
+	return UserInfoCard{
+		SupplementalDisplayName:     u.SupplementalDisplayName,
+		IconPath:                    u.IconPath,
+		CrossSaveOverride:           u.CrossSaveOverride,
+		ApplicableMembershipTypes:   u.ApplicableMembershipTypes,
+		IsPublic:                    u.IsPublic,
+		MembershipType:              u.MembershipType,
+		MembershipID:                u.MembershipID,
+		DisplayName:                 u.DisplayName,
+		BungieGlobalDisplayName:     u.BungieGlobalDisplayName,
+		BungieGlobalDisplayNameCode: u.BungieGlobalDisplayNameCode,
+	}
+}
+
+// UserInfoCard returns the card as a UserInfoCard
+func (u DestinyProfileUserInfoCard) UserInfoCard() UserInfoCard {
+	return UserInfoCard{
+		SupplementalDisplayName:     u.SupplementalDisplayName,
+		IconPath:                    u.IconPath,
+		CrossSaveOverride:           u.CrossSaveOverride,
+		ApplicableMembershipTypes:   u.ApplicableMembershipTypes,
+		IsPublic:                    u.IsPublic,
+		MembershipType:              u.MembershipType,
+		MembershipID:                u.MembershipID,
+		DisplayName:                 u.DisplayName,
+		BungieGlobalDisplayName:     u.BungieGlobalDisplayName,
+		BungieGlobalDisplayNameCode: u.BungieGlobalDisplayNameCode,
+	}
+}
+
+// PrimaryMembership returns the membership the user plays Destiny 2 on. Bungie's primary membership
+// ID is preferred, otherwise the rules of the PrimaryMembership function are followed
+func (d UserMembershipData) PrimaryMembership() (UserInfoCard, bool) {
+	cards := make([]UserInfoCard, len(d.DestinyMemberships))
+	for i, membership := range d.DestinyMemberships {
+		if d.PrimaryMembershipID != 0 && membership.MembershipID == d.PrimaryMembershipID {
+			return membership.UserInfoCard(), true
+		}
+
+		cards[i] = membership.UserInfoCard()
+	}
+
+	return PrimaryMembership(cards)
+}
+
+// PrimaryMembership returns the profile the user plays Destiny 2 on. The profile Bungie marks as the
+// cross save primary is preferred, otherwise the rules of the PrimaryMembership function are followed
+func (r DestinyLinkedProfilesResponse) PrimaryMembership() (UserInfoCard, bool) {
+	cards := make([]UserInfoCard, len(r.Profiles))
+	for i, profile := range r.Profiles {
+		if profile.IsCrossSavePrimary {
+			return profile.UserInfoCard(), true
+		}
+
+		cards[i] = profile.UserInfoCard()
+	}
+
+	return PrimaryMembership(cards)
+}
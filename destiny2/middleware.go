@@ -0,0 +1,78 @@
+package destiny2
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Response is a response from the Bungie API with the envelope decoded and the body of the
+// Response field left raw so it can be decoded into the type the endpoint returns
+type Response struct {
+	APIResponse
+	StatusCode int
+	Header     http.Header
+	Body       json.RawMessage
+}
+
+// Doer sends a request to the Bungie API. When the API responds with an error both the decoded
+// response and an *APIError are returned
+type Doer interface {
+	Do(req *http.Request) (*Response, error)
+}
+
+// DoerFunc is an adapter to allow ordinary functions to be used as a Doer
+type DoerFunc func(req *http.Request) (*Response, error)
+
+// Do calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Doer to intercept every exchange with the Bungie API. Middleware sees a request
+// once no matter how many times it is retried
+type Middleware = func(next Doer) Doer
+
+// Use adds middleware to the client. Middleware is called in the order it is added, so the first
+// middleware added sees the request first and the response last. Function returns self for ease
+// of chaining
+func (c *Client) Use(mw ...Middleware) *Client {
+	middleware := make([]Middleware, 0, len(c.middleware)+len(mw))
+	middleware = append(middleware, c.middleware...)
+	c.middleware = append(middleware, mw...)
+
+	return c
+}
+
+// doer returns the client's middleware chain wrapped around the exchange with the Bungie API
+func (c *Client) doer() Doer {
+	var d Doer = c.flightDoer(DoerFunc(c.exchange))
+	if c.cache != nil {
+		d = c.cacheDoer(d)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		d = c.middleware[i](d)
+	}
+
+	return d
+}
+
+// LoggingMiddleware logs every exchange with the Bungie API using logf, which can be log.Printf
+func LoggingMiddleware(logf func(format string, args ...interface{})) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			took := time.Since(start)
+
+			switch {
+			case resp != nil:
+				logf("destiny2: %s %s -> %d %s (%s)", req.Method, req.URL.Path, resp.StatusCode, resp.ErrorStatus, took)
+			case err != nil:
+				logf("destiny2: %s %s -> %s (%s)", req.Method, req.URL.Path, err.Error(), took)
+			}
+
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package destiny2
+
+import (
+	"context"
+)
+
+// SearchResult is the paging information included in every paged response from the Bungie API
+type SearchResult struct {
+	TotalResults                 int        `json:"totalResults"`
+	HasMore                      bool       `json:"hasMore"`
+	Query                        PagedQuery `json:"query"`
+	ReplacementContinuationToken string     `json:"replacementContinuationToken"`
+	UseTotalResults              bool       `json:"useTotalResults"`
+}
+
+// PageFunc fetches a single page of results. The options passed to the function set the page to fetch
+// and must be passed along to the endpoint being paged through
+type PageFunc func(ctx context.Context, opts ...RequestOption) (SearchResult, error)
+
+// Pager steps through the pages of a paged endpoint one page at a time. The results of each page are
+// kept by the PageFunc the pager was created with.
+//
+//	var page destiny2.SearchResultOfGroupMember
+//	p := destiny2.NewPager(func(ctx context.Context, opts ...destiny2.RequestOption) (destiny2.SearchResult, error) {
+//		var err error
+//		page, err = client.GroupV2Service.GetMembersOfGroup(ctx, gid, opts...)
+//		return page.SearchResult, err
+//	})
+//	for p.Next(ctx) {
+//		// Use page.Results
+//	}
+//	if err := p.Err(); err != nil {
+//		// Handle error
+//	}
+type Pager struct {
+	fetch    PageFunc
+	opts     []RequestOption
+	pageSize int
+	page     int
+	token    string
+	done     bool
+	err      error
+}
+
+// NewPager creates a pager that fetches pages with fetch. Any options provided are passed to fetch
+// for every page
+func NewPager(fetch PageFunc, opts ...RequestOption) *Pager {
+	return &Pager{
+		fetch: fetch,
+		opts:  opts,
+	}
+}
+
+// SetPageSize sets the number of items requested for every page. Not every endpoint allows the page
+// size to be changed. Function returns self for ease of chaining
+func (p *Pager) SetPageSize(n int) *Pager {
+	p.pageSize = n
+	return p
+}
+
+// Next fetches the next page. False is returned when there are no pages left or an error occurred,
+// in which case the error can be retrieved with Pager.Err
+func (p *Pager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	p.page++
+
+	// Options for paging are applied last so they replace any provided by the caller
+	opts := append([]RequestOption{}, p.opts...)
+	opts = append(opts, OptionSetQuery("currentPage", p.page))
+	if p.pageSize > 0 {
+		opts = append(opts, OptionSetQuery("itemsPerPage", p.pageSize))
+	}
+	if p.token != "" {
+		opts = append(opts, OptionSetQuery("requestContinuationToken", p.token))
+	}
+
+	result, err := p.fetch(ctx, opts...)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	p.token = result.ReplacementContinuationToken
+	p.done = !result.HasMore
+
+	return true
+}
+
+// Page returns the number of the page last fetched by Pager.Next. Pages start at 1
+func (p *Pager) Page() int {
+	return p.page
+}
+
+// Err returns the error that stopped the pager, if any
+func (p *Pager) Err() error {
+	return p.err
+}
@@ -0,0 +1,99 @@
+package destiny2
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+
+	// DefaultRate is the number of requests per second a client is allowed to make by default
+	DefaultRate = 20
+
+	// DefaultBurst is the number of requests a client is allowed to make at once by default
+	DefaultBurst = 20
+)
+
+// RateLimiter is a token bucket that limits how often requests can be made. A RateLimiter
+// is safe to use from multiple go routines
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a rate limiter that allows rate requests per second with bursts of up
+// to burst requests. A rate of 0 or less disables the limiter
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// SetLimit changes the rate and burst of the limiter. Requests that are already waiting will be
+// allowed based on the new limit
+func (l *RateLimiter) SetLimit(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rate = rate
+	l.burst = float64(burst)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// Wait blocks until a request is allowed to be made or ctx is done. If ctx is done before a request
+// is allowed the context's error is returned
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		d := l.reserve()
+		if d == 0 {
+			return nil
+		}
+
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token from the bucket if one is available. If there are no tokens available
+// the time until the next token is available is returned instead
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	// Refilling the bucket with the tokens that were generated since the last call
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
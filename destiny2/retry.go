@@ -0,0 +1,173 @@
+package destiny2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried
+type RetryPolicy struct {
+
+	// MaxAttempts is the maximum number of times a request will be sent, including the first attempt.
+	// A value of 1 or less disables retries
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles for every retry after that
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries. Delays requested by Bungie through ThrottleSeconds
+	// or the Retry-After header are not capped
+	MaxDelay time.Duration
+
+	// RetryNonIdempotent allows requests that are not GETs to be retried
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is the retry policy used by clients that have not been given one
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// NoRetries is a retry policy that disables retries
+var NoRetries = RetryPolicy{MaxAttempts: 1}
+
+// OptionRetryPolicy overrides the client's retry policy for a single request
+func OptionRetryPolicy(p RetryPolicy) RequestOption {
+	return func(req *http.Request) *http.Request {
+		ctx := context.WithValue(req.Context(), retryPolicyKey, p)
+		return req.WithContext(ctx)
+	}
+}
+
+// retryPolicyFromRequest returns the retry policy set on the request with OptionRetryPolicy. If
+// the request does not have one, def is returned instead
+func retryPolicyFromRequest(req *http.Request, def RetryPolicy) RetryPolicy {
+	if p, ok := req.Context().Value(retryPolicyKey).(RetryPolicy); ok {
+		return p
+	}
+
+	return def
+}
+
+// allows returns true if the policy allows req to be retried
+func (p RetryPolicy) allows(req *http.Request) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+
+	return req.Method == http.MethodGet || p.RetryNonIdempotent
+}
+
+// backoff returns how long to wait before sending the next attempt. attempt is the number of
+// attempts that have already been sent
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+
+	// Bungie told us how long to wait so we listen
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.ThrottleSeconds > 0 {
+			return time.Duration(apiErr.ThrottleSeconds) * time.Second
+		}
+		if apiErr.retryAfter > 0 {
+			return apiErr.retryAfter
+		}
+	}
+
+	d := p.BaseDelay << uint(attempt-1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Full jitter so concurrent callers do not retry in lock step
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+// shouldRetry returns true if err was caused by something that may not happen again if
+// the request is resent
+func shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	// Errors sending the request are worth retrying but errors decoding the body are not
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return apiErr.ErrorCode.IsRetryable()
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which can either be a number of
+// seconds or an HTTP date
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+// sleep waits for d to elapse or ctx to be done, whichever happens first
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// rewindableBody makes sure the body of req can be read again for every retry
+func rewindableBody(req *http.Request) error {
+	if req.Body == nil || req.GetBody != nil {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return nil
+}
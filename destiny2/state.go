@@ -0,0 +1,225 @@
+package destiny2
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// AuthState is the information carried through the authorize flow by a state value
+type AuthState struct {
+	UserID       string
+	GuildID      string
+	Expires      time.Time
+	CodeVerifier string
+}
+
+// statePayload is the signed part of a state value
+type statePayload struct {
+	Nonce   string `json:"n"`
+	UserID  string `json:"u"`
+	GuildID string `json:"g"`
+	Expires int64  `json:"e"`
+}
+
+// MinStateKeyLength is the shortest key a StateManager accepts
+const MinStateKeyLength = 32
+
+// NonceStore remembers the nonces of verified states so a StateManager can reject states that are reused.
+// Implementations must be safe to use from multiple go routines
+type NonceStore interface {
+
+	// Use marks the nonce as used until expires. False is returned if the nonce has already been used
+	Use(nonce string, expires time.Time) (bool, error)
+}
+
+// StateManager issues and verifies the state values used in the authorize flow. States are signed so
+// they can't be forged, expire so they can't be used forever and can only be used once. Every state also
+// has a PKCE code verifier derived from it so the code can only be exchanged by whoever issued the state.
+// A StateManager is safe to use from multiple go routines.
+//
+// By default used states are remembered in memory, so a state can be used again after a restart or on
+// another instance of the app. Use SetNonceStore to share used states between instances
+type StateManager struct {
+	key    []byte
+	ttl    time.Duration
+	nonces NonceStore
+}
+
+// NewStateManager creates a state manager that signs states with key. States expire after ttl.
+// ErrShortStateKey is returned if key is shorter than MinStateKeyLength
+func NewStateManager(key []byte, ttl time.Duration) (*StateManager, error) {
+	if len(key) < MinStateKeyLength {
+		return nil, ErrShortStateKey
+	}
+
+	return &StateManager{
+		key:    key,
+		ttl:    ttl,
+		nonces: &memoryNonceStore{used: map[string]time.Time{}},
+	}, nil
+}
+
+// SetNonceStore sets the store used to remember which states have been verified. Function returns self
+// for ease of chaining
+func (m *StateManager) SetNonceStore(store NonceStore) *StateManager {
+	m.nonces = store
+	return m
+}
+
+// Issue issues a new state for the user authorizing the app from the provided guild
+func (m *StateManager) Issue(userID, guildID string) (string, error) {
+	state, _, err := m.issue(userID, guildID)
+	return state, err
+}
+
+// issue issues a new state and returns it along with its nonce
+func (m *StateManager) issue(userID, guildID string) (string, string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+
+	payload, err := json.Marshal(statePayload{
+		Nonce:   nonce,
+		UserID:  userID,
+		GuildID: guildID,
+		Expires: time.Now().Add(m.ttl).Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + m.sign("state:"+encoded), nonce, nil
+}
+
+// Verify verifies the state and returns the information it carries. Once a state has been verified it
+// can't be verified again.
+//
+// ErrInvalidState is returned if the state was not issued by the manager, ErrStateExpired if it has expired
+// and ErrStateReused if it has already been verified
+func (m *StateManager) Verify(state string) (AuthState, error) {
+	parts := strings.Split(state, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(m.sign("state:"+parts[0]))) {
+		return AuthState{}, ErrInvalidState
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return AuthState{}, ErrInvalidState
+	}
+	p := statePayload{}
+	if err = json.Unmarshal(b, &p); err != nil {
+		return AuthState{}, ErrInvalidState
+	}
+
+	now := time.Now()
+	expires := time.Unix(p.Expires, 0)
+	if now.After(expires) {
+		return AuthState{}, ErrStateExpired
+	}
+
+	fresh, err := m.nonces.Use(p.Nonce, expires)
+	if err != nil {
+		return AuthState{}, err
+	}
+	if !fresh {
+		return AuthState{}, ErrStateReused
+	}
+
+	return AuthState{
+		UserID:       p.UserID,
+		GuildID:      p.GuildID,
+		Expires:      expires,
+		CodeVerifier: m.verifier(p.Nonce),
+	}, nil
+}
+
+// AuthURL issues a state for the user and returns the URL to send the user to so they can authorize the
+// app. The URL includes a PKCE code challenge
+func (m *StateManager) AuthURL(c *Client, userID, guildID string) (string, error) {
+	state, nonce, err := m.issue(userID, guildID)
+	if err != nil {
+		return "", err
+	}
+
+	return c.GetAuthURLWithPKCE(state, m.verifier(nonce)), nil
+}
+
+// Exchange verifies the state returned to the redirect URL and exchanges the code for a token using
+// the state's PKCE code verifier
+func (m *StateManager) Exchange(ctx context.Context, c *Client, state, code string) (AuthState, *oauth2.Token, error) {
+	s, err := m.Verify(state)
+	if err != nil {
+		return s, nil, err
+	}
+
+	t, err := c.ExchangeWithPKCE(ctx, code, s.CodeVerifier)
+	return s, t, err
+}
+
+// verifier derives the PKCE code verifier of the state with the provided nonce
+func (m *StateManager) verifier(nonce string) string {
+	return m.sign("pkce:" + nonce)
+}
+
+// sign returns the base64 encoded HMAC of s
+func (m *StateManager) sign(s string) string {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(s))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// memoryNonceStore is a NonceStore that remembers nonces in memory until they expire
+type memoryNonceStore struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// Use implements NonceStore
+func (s *memoryNonceStore) Use(nonce string, expires time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Forgetting states that have expired since they would be rejected anyway
+	now := time.Now()
+	for n, exp := range s.used {
+		if now.After(exp) {
+			delete(s.used, n)
+		}
+	}
+
+	if _, ok := s.used[nonce]; ok {
+		return false, nil
+	}
+	s.used[nonce] = expires
+
+	return true, nil
+}
+
+// GetAuthURLWithPKCE generates an auth URL like Client.GetAuthURL but includes the S256 code challenge
+// of the provided PKCE code verifier. The same verifier must be passed to Client.ExchangeWithPKCE
+func (c *Client) GetAuthURLWithPKCE(state, verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return c.oauth2Config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", challenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+}
+
+// ExchangeWithPKCE exchanges a code obtained from a URL generated by Client.GetAuthURLWithPKCE for a token
+func (c *Client) ExchangeWithPKCE(ctx context.Context, code, verifier string) (*oauth2.Token, error) {
+	return c.oauth2Config.Exchange(c.oauthContext(ctx), code, oauth2.SetAuthURLParam("code_verifier", verifier))
+}
@@ -0,0 +1,152 @@
+package destiny2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DestinyStatsGroupType ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-Definitions-DestinyStatsGroupType.html#schema_Destiny-HistoricalStats-Definitions-DestinyStatsGroupType
+type DestinyStatsGroupType int
+
+// DestinyStatsGroupType values
+const (
+	StatsGroupNone         DestinyStatsGroupType = 0
+	StatsGroupGeneral      DestinyStatsGroupType = 1
+	StatsGroupWeapons      DestinyStatsGroupType = 2
+	StatsGroupMedals       DestinyStatsGroupType = 3
+	StatsGroupLeaderboard  DestinyStatsGroupType = 101
+	StatsGroupActivity     DestinyStatsGroupType = 102
+	StatsGroupUniqueWeapon DestinyStatsGroupType = 103
+)
+
+// PeriodType ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-Definitions-PeriodType.html#schema_Destiny-HistoricalStats-Definitions-PeriodType
+type PeriodType int
+
+// PeriodType values
+const (
+	PeriodTypeNone     PeriodType = 0
+	PeriodTypeDaily    PeriodType = 1
+	PeriodTypeAllTime  PeriodType = 2
+	PeriodTypeActivity PeriodType = 3
+)
+
+// DestinyHistoricalStatsByPeriod ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsByPeriod.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsByPeriod
+type DestinyHistoricalStatsByPeriod struct {
+	AllTime      DestinyHistoricalStatsValues        `json:"allTime"`
+	AllTimeTier1 DestinyHistoricalStatsValues        `json:"allTimeTier1"`
+	AllTimeTier2 DestinyHistoricalStatsValues        `json:"allTimeTier2"`
+	AllTimeTier3 DestinyHistoricalStatsValues        `json:"allTimeTier3"`
+	Daily        []DestinyHistoricalStatsPeriodGroup `json:"daily"`
+	Monthly      []DestinyHistoricalStatsPeriodGroup `json:"monthly"`
+}
+
+// DestinyHistoricalStatsAccountResult ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsAccountResult.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsAccountResult
+type DestinyHistoricalStatsAccountResult struct {
+	MergedDeletedCharacters DestinyHistoricalStatsWithMerged     `json:"mergedDeletedCharacters"`
+	MergedAllCharacters     DestinyHistoricalStatsWithMerged     `json:"mergedAllCharacters"`
+	Characters              []DestinyHistoricalStatsPerCharacter `json:"characters"`
+}
+
+// DestinyHistoricalStatsWithMerged ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsWithMerged.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsWithMerged
+type DestinyHistoricalStatsWithMerged struct {
+	Results map[string]DestinyHistoricalStatsByPeriod `json:"results"`
+	Merged  DestinyHistoricalStatsByPeriod            `json:"merged"`
+}
+
+// DestinyHistoricalStatsPerCharacter ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalStatsPerCharacter.html#schema_Destiny-HistoricalStats-DestinyHistoricalStatsPerCharacter
+type DestinyHistoricalStatsPerCharacter struct {
+	CharacterID int64                                     `json:"characterId,string"`
+	Deleted     bool                                      `json:"deleted"`
+	Results     map[string]DestinyHistoricalStatsByPeriod `json:"results"`
+	Merged      DestinyHistoricalStatsByPeriod            `json:"merged"`
+}
+
+// DestinyHistoricalWeaponStatsData ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyHistoricalWeaponStatsData.html#schema_Destiny-HistoricalStats-DestinyHistoricalWeaponStatsData
+type DestinyHistoricalWeaponStatsData struct {
+	Weapons []DestinyHistoricalWeaponStats `json:"weapons"`
+}
+
+// DestinyClanAggregateStat ...
+// https://bungie-net.github.io/multi/schema_Destiny-HistoricalStats-DestinyClanAggregateStat.html#schema_Destiny-HistoricalStats-DestinyClanAggregateStat
+type DestinyClanAggregateStat struct {
+	Mode   DestinyActivityModeType     `json:"mode"`
+	StatID string                      `json:"statId"`
+	Value  DestinyHistoricalStatsValue `json:"value"`
+}
+
+// OptionDayRange limits daily historical stats to the days between start and end inclusive
+func OptionDayRange(start, end time.Time) RequestOption {
+	return func(req *http.Request) *http.Request {
+		req = OptionSetQuery("daystart", start.Format("2006-01-02"))(req)
+		return OptionSetQuery("dayend", end.Format("2006-01-02"))(req)
+	}
+}
+
+// statsQuery returns options that add the provided modes and groups to a request, omitting empty ones
+func statsQuery(modes []DestinyActivityModeType, groups []DestinyStatsGroupType, opts []RequestOption) []RequestOption {
+	query := []RequestOption{}
+	if len(modes) > 0 {
+		ints := make([]int, len(modes))
+		for i, mode := range modes {
+			ints[i] = int(mode)
+		}
+		query = append(query, OptionQuery("modes", joinInts(ints)))
+	}
+	if len(groups) > 0 {
+		ints := make([]int, len(groups))
+		for i, group := range groups {
+			ints[i] = int(group)
+		}
+		query = append(query, OptionQuery("groups", joinInts(ints)))
+	}
+
+	return append(query, opts...)
+}
+
+// GetHistoricalStats gets the historical stats of the given character for the provided modes. The
+// results are keyed by mode. If characterID is 0 stats are merged across all the account's characters.
+// Use OptionDayRange to limit daily stats to a range of days
+func (ds *Destiny2Service) GetHistoricalStats(ctx context.Context, membershipType BungieMembershipType, membershipID, characterID int64, periodType PeriodType, modes []DestinyActivityModeType, groups []DestinyStatsGroupType, opts ...RequestOption) (map[string]DestinyHistoricalStatsByPeriod, error) {
+	r := map[string]DestinyHistoricalStatsByPeriod{}
+	endpoint := fmt.Sprintf("/%d/Account/%d/Character/%d/Stats", membershipType, membershipID, characterID)
+	opts = statsQuery(modes, groups, opts)
+	if periodType != PeriodTypeNone {
+		opts = append([]RequestOption{OptionQuery("periodType", int(periodType))}, opts...)
+	}
+
+	err := ds.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// GetHistoricalStatsForAccount gets the aggregated historical stats of every character of the given account
+func (ds *Destiny2Service) GetHistoricalStatsForAccount(ctx context.Context, membershipType BungieMembershipType, membershipID int64, groups []DestinyStatsGroupType, opts ...RequestOption) (DestinyHistoricalStatsAccountResult, error) {
+	r := DestinyHistoricalStatsAccountResult{}
+	endpoint := fmt.Sprintf("/%d/Account/%d/Stats", membershipType, membershipID)
+	err := ds.do(ctx, "GET", endpoint, &r, statsQuery(nil, groups, opts)...)
+	return r, err
+}
+
+// GetUniqueWeaponHistory gets the stats of the exotic weapons the given character has used
+func (ds *Destiny2Service) GetUniqueWeaponHistory(ctx context.Context, membershipType BungieMembershipType, membershipID, characterID int64, opts ...RequestOption) (DestinyHistoricalWeaponStatsData, error) {
+	r := DestinyHistoricalWeaponStatsData{}
+	endpoint := fmt.Sprintf("/%d/Account/%d/Character/%d/Stats/UniqueWeapons", membershipType, membershipID, characterID)
+	err := ds.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// GetClanAggregateStats gets the aggregated stats of the given clan for the provided modes
+func (ds *Destiny2Service) GetClanAggregateStats(ctx context.Context, gid int64, modes []DestinyActivityModeType, opts ...RequestOption) ([]DestinyClanAggregateStat, error) {
+	r := []DestinyClanAggregateStat{}
+	endpoint := fmt.Sprintf("/Stats/AggregateClanStats/%d", gid)
+	err := ds.do(ctx, "GET", endpoint, &r, statsQuery(modes, nil, opts)...)
+	return r, err
+}
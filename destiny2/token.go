@@ -0,0 +1,180 @@
+package destiny2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenStore loads and saves the OAuth tokens of users. Bungie rotates refresh tokens every time
+// they are used so every token passed to SaveToken must be persisted or the user will have to
+// authorize the app again
+type TokenStore interface {
+
+	// LoadToken loads the token of the user with the provided membership ID
+	LoadToken(ctx context.Context, membershipID int64) (*oauth2.Token, error)
+
+	// SaveToken saves the token of the user with the provided membership ID
+	SaveToken(ctx context.Context, membershipID int64, t *oauth2.Token) error
+}
+
+// LockingTokenStore is a TokenStore that can lock a user's token so only one process refreshes it
+// at a time. Stores shared by more than one process should implement it
+type LockingTokenStore interface {
+	TokenStore
+
+	// WithTokenLock locks the token of the user with the provided membership ID and calls fn with the
+	// user's current token. The token returned by fn is saved before the lock is released. If fn
+	// returns an error nothing is saved
+	WithTokenLock(ctx context.Context, membershipID int64, fn func(current *oauth2.Token) (*oauth2.Token, error)) (*oauth2.Token, error)
+}
+
+// refreshGroup makes sure only one refresh is in flight per user
+type refreshGroup struct {
+	mu    sync.Mutex
+	calls map[int64]*refreshCall
+}
+
+// refreshCall is a refresh that is in flight or has completed
+type refreshCall struct {
+	done  chan struct{}
+	token *oauth2.Token
+	err   error
+}
+
+// newRefreshGroup creates an empty refresh group
+func newRefreshGroup() *refreshGroup {
+	return &refreshGroup{calls: map[int64]*refreshCall{}}
+}
+
+// do calls fn for the user, unless a refresh for the user is already in flight in which case the
+// caller waits for it to complete, or for ctx to be done, and shares its token
+func (g *refreshGroup) do(ctx context.Context, membershipID int64, fn func() (*oauth2.Token, error)) (*oauth2.Token, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[membershipID]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+
+		return call.token, call.err
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	g.calls[membershipID] = call
+	g.mu.Unlock()
+
+	call.token, call.err = fn()
+	close(call.done)
+
+	g.mu.Lock()
+	delete(g.calls, membershipID)
+	g.mu.Unlock()
+
+	return call.token, call.err
+}
+
+// refreshToken refreshes the stale token of the user and saves the new token to store. Concurrent refreshes
+// for the same user share a single refresh, and if the user's token was already refreshed by someone else
+// the token in store is used instead of refreshing it again, since refresh tokens can only be used once
+func (c *Client) refreshToken(ctx context.Context, store TokenStore, membershipID int64, stale *oauth2.Token) (*oauth2.Token, error) {
+	return c.refreshes.do(ctx, membershipID, func() (*oauth2.Token, error) {
+		refresh := func(current *oauth2.Token) (*oauth2.Token, error) {
+			if current == nil {
+				return nil, ErrNoToken
+			}
+			if current.RefreshToken != stale.RefreshToken && current.Valid() {
+				return current, nil
+			}
+
+			// Forcing a refresh by leaving out the access token
+			ts := c.oauth2Config.TokenSource(c.oauthContext(ctx), &oauth2.Token{RefreshToken: current.RefreshToken})
+			return ts.Token()
+		}
+
+		if locker, ok := store.(LockingTokenStore); ok {
+			return locker.WithTokenLock(ctx, membershipID, refresh)
+		}
+
+		current, err := store.LoadToken(ctx, membershipID)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := refresh(current)
+		if err != nil || t == current {
+			return t, err
+		}
+
+		if err = store.SaveToken(ctx, membershipID, t); err != nil {
+			return nil, fmt.Errorf("destiny2: saving refreshed token: %w", err)
+		}
+
+		return t, nil
+	})
+}
+
+// storingTokenSource is a token source that refreshes tokens through the client so that refreshed tokens
+// are saved and refreshes for the same user are coordinated
+type storingTokenSource struct {
+	ctx          context.Context
+	mu           sync.Mutex
+	c            *Client
+	store        TokenStore
+	membershipID int64
+	token        *oauth2.Token
+}
+
+// Token implements oauth2.TokenSource
+func (s *storingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Valid() {
+		return s.token, nil
+	}
+
+	t, err := s.c.refreshToken(s.ctx, s.store, s.membershipID, s.token)
+	if err != nil {
+		return nil, err
+	}
+	s.token = t
+
+	return t, nil
+}
+
+// ForUser returns a copy of Client that is authorized as the user with the provided membership ID. The
+// user's token is loaded from store and, whenever it is refreshed, the new token is saved back to store.
+// ctx is used when the token needs to be refreshed so it should live as long as the returned client is used.
+// ErrNoToken is returned if store has no token for the user
+func (c Client) ForUser(ctx context.Context, store TokenStore, membershipID int64) (*Client, error) {
+	t, err := store.LoadToken(ctx, membershipID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrNoToken
+	}
+
+	// Refreshing with a copy of the client that is not authorized as the user, otherwise refreshing
+	// the token would need the token
+	base := c
+	ts := &storingTokenSource{
+		ctx:          ctx,
+		c:            &base,
+		store:        store,
+		membershipID: membershipID,
+		token:        t,
+	}
+
+	c.SetHTTPClient(oauth2.NewClient(c.oauthContext(ctx), ts))
+	c.authenticated = true
+	c.initServices()
+
+	return &c, nil
+}
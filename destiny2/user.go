@@ -1,6 +1,8 @@
 package destiny2
 
 import (
+	"context"
+	"fmt"
 	"path"
 	"time"
 )
@@ -12,8 +14,30 @@ type UserService struct {
 // UserMembershipData ...
 // https://bungie-net.github.io/multi/schema_User-UserMembershipData.html#schema_User-UserMembershipData
 type UserMembershipData struct {
-	DestinyMemberships []GroupUserInfoCard
-	BungieNetUser      GeneralUser
+	DestinyMemberships  []GroupUserInfoCard `json:"destinyMemberships"`
+	BungieNetUser       GeneralUser         `json:"bungieNetUser"`
+	PrimaryMembershipID int64               `json:"primaryMembershipId,string"`
+}
+
+// UserInfoCard ...
+// https://bungie-net.github.io/multi/schema_User-UserInfoCard.html#schema_User-UserInfoCard
+type UserInfoCard struct {
+	SupplementalDisplayName     string                 `json:"supplementalDisplayName"`
+	IconPath                    string                 `json:"iconPath"`
+	CrossSaveOverride           BungieMembershipType   `json:"crossSaveOverride"`
+	ApplicableMembershipTypes   []BungieMembershipType `json:"applicableMembershipTypes"`
+	IsPublic                    bool                   `json:"isPublic"`
+	MembershipType              BungieMembershipType   `json:"membershipType"`
+	MembershipID                int64                  `json:"membershipId,string"`
+	DisplayName                 string                 `json:"displayName"`
+	BungieGlobalDisplayName     string                 `json:"bungieGlobalDisplayName"`
+	BungieGlobalDisplayNameCode int                    `json:"bungieGlobalDisplayNameCode"`
+}
+
+// BungieName returns the user's Bungie Name in the name#1234 form, or an empty string if the user
+// has not been assigned one
+func (u UserInfoCard) BungieName() string {
+	return FormatBungieName(u.BungieGlobalDisplayName, u.BungieGlobalDisplayNameCode)
 }
 
 // GeneralUser ...
@@ -67,14 +91,65 @@ type IgnoreResponse struct {
 	IgnoreFlags int  `json:"ignoreFlags"`
 }
 
+// UserSearchPrefixRequest ...
+// https://bungie-net.github.io/multi/schema_User-UserSearchPrefixRequest.html#schema_User-UserSearchPrefixRequest
+type UserSearchPrefixRequest struct {
+	DisplayNamePrefix string `json:"displayNamePrefix"`
+}
+
+// UserSearchResponse ...
+// https://bungie-net.github.io/multi/schema_User-UserSearchResponse.html#schema_User-UserSearchResponse
+type UserSearchResponse struct {
+	SearchResults []UserSearchResponseDetail `json:"searchResults"`
+	Page          int                        `json:"page"`
+	HasMore       bool                       `json:"hasMore"`
+}
+
+// UserSearchResponseDetail ...
+// https://bungie-net.github.io/multi/schema_User-UserSearchResponseDetail.html#schema_User-UserSearchResponseDetail
+type UserSearchResponseDetail struct {
+	BungieGlobalDisplayName     string         `json:"bungieGlobalDisplayName"`
+	BungieGlobalDisplayNameCode int            `json:"bungieGlobalDisplayNameCode"`
+	BungieNetMembershipID       int64          `json:"bungieNetMembershipId,string"`
+	DestinyMemberships          []UserInfoCard `json:"destinyMemberships"`
+}
+
+// BungieName returns the user's Bungie Name in the name#1234 form
+func (d UserSearchResponseDetail) BungieName() string {
+	return FormatBungieName(d.BungieGlobalDisplayName, d.BungieGlobalDisplayNameCode)
+}
+
 // GetMembershipDataForCurrentUser returns a list of accounts associated with signed in user.
-func (us *UserService) GetMembershipDataForCurrentUser(opts ...RequestOption) (UserMembershipData, error) {
+func (us *UserService) GetMembershipDataForCurrentUser(ctx context.Context, opts ...RequestOption) (UserMembershipData, error) {
+	r := UserMembershipData{}
+	err := us.do(ctx, "GET", "/GetMembershipsForCurrentUser", &r, opts...)
+	return r, err
+}
+
+// GetMembershipDataByID returns a list of accounts associated with the supplied membership ID and
+// membership type. This endpoint does not require the user to have authorized the application
+func (us *UserService) GetMembershipDataByID(ctx context.Context, membershipID int64, membershipType BungieMembershipType, opts ...RequestOption) (UserMembershipData, error) {
 	r := UserMembershipData{}
-	err := us.do("GET", "/GetMembershipsForCurrentUser", &r, opts...)
+	endpoint := fmt.Sprintf("/GetMembershipsById/%d/%d", membershipID, membershipType)
+	err := us.do(ctx, "GET", endpoint, &r, opts...)
+	return r, err
+}
+
+// SearchByGlobalName searches for users whose Bungie Name starts with prefix. Pages start at 0
+func (us *UserService) SearchByGlobalName(ctx context.Context, prefix string, page int, opts ...RequestOption) (UserSearchResponse, error) {
+	r := UserSearchResponse{}
+	body, err := OptionJSONBody(UserSearchPrefixRequest{DisplayNamePrefix: prefix})
+	if err != nil {
+		return r, err
+	}
+
+	endpoint := fmt.Sprintf("/Search/GlobalName/%d", page)
+	opts = append([]RequestOption{body}, opts...)
+	err = us.do(ctx, "POST", endpoint, &r, opts...)
 	return r, err
 }
 
-func (us *UserService) do(method, endpoint string, dst interface{}, opts ...RequestOption) error {
+func (us *UserService) do(ctx context.Context, method, endpoint string, dst interface{}, opts ...RequestOption) error {
 	endpoint = path.Join("/User", endpoint)
-	return us.c.do(method, endpoint, dst, opts...)
+	return us.c.do(ctx, method, endpoint, dst, opts...)
 }
@@ -62,6 +62,11 @@ func main() {
 		viper.GetString("BUNGIE_CLIENT_SECRET"),
 	)
 
+	// Caching public responses is opt in since cached profiles can be out of date
+	if size := viper.GetInt("BUNGIE_CACHE_SIZE"); size > 0 {
+		d2Client.SetCache(destiny2.NewMemoryCache(size))
+	}
+
 	app := app.New(d2Client, c, repo)
 	app.RunUntilInterupt()
 }